@@ -11,7 +11,28 @@ import (
 	"github.com/fatih/color"
 )
 
-const PROMPT = ">> "
+const (
+	PROMPT       = ">> "
+	CONTINUATION = ".. "
+)
+
+// Options changes how the REPL looks, it doesn't change how code is evaluated
+type Options struct {
+	Prompt       string
+	Continuation string
+	Quiet        bool
+}
+
+func DefaultOptions() Options {
+	return Options{Prompt: PROMPT, Continuation: CONTINUATION}
+}
+
+// prompts gives the prompt of a new input and the one of a continued block
+func (options Options) prompts() (string, string) {
+	var prompt bytes.Buffer
+	color.New(color.FgGreen).Fprint(&prompt, options.Prompt)
+	return prompt.String(), options.Continuation
+}
 
 func welcomeBoard() {
 	color.Blue("Chào mừng đến với VanVo 0.1.0")
@@ -21,23 +42,26 @@ func welcomeBoard() {
 	color.Blue(`    └┘ ┴ ┴┘└┘   └┘ └─┘ `)
 }
 
-func Start() {
-	var prompt bytes.Buffer
-	color.New(color.FgGreen).Fprint(&prompt, PROMPT)
+func Start(config *evaluator.Config, options Options) {
+	prompt, continuation := options.prompts()
 
-	rl, err := readline.New(prompt.String())
+	rl, err := readline.New(prompt)
 	if err != nil {
 		panic(err)
 	}
 	defer rl.Close()
 
-	welcomeBoard()
+	if !options.Quiet {
+		welcomeBoard()
+	}
 
 	blockInput := ""
 	env := object.NewEnvironment()
 	for {
 		line, err := rl.Readline()
-		line = strings.Trim(line, " ")
+		// leading spaces are the indentation of a block, they also keep the
+		// columns of errors where the user typed them
+		line = strings.TrimRight(line, " ")
 		spaces := strings.Repeat(" ", 4)
 		line = strings.ReplaceAll(line, "\t", spaces)
 
@@ -54,22 +78,23 @@ func Start() {
 
 		if line == "" {
 			blockInput = ""
-			rl.SetPrompt(prompt.String())
+			rl.SetPrompt(prompt)
 		}
 
 		if lastWord == ':' || lastWord == '(' {
 			blockInput = input + "\n"
-			rl.SetPrompt(".. ")
+			rl.SetPrompt(continuation)
 		}
 
 		if blockInput == "" {
-			value, errors := evaluator.EvalFromInput(input, "", env)
+			value, errors := evaluator.EvalFromInput(input, "", env, config)
 
+			fmt.Fprint(config.Stdout, errors.WarningString())
 			if errors.NotEmpty() {
-				fmt.Print(errors)
+				fmt.Fprint(config.Stdout, errors)
 
 			} else if value != evaluator.NO_PRINT {
-				fmt.Println(value.Display())
+				fmt.Fprintln(config.Stdout, value.Display())
 			}
 
 		} else {
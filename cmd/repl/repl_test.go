@@ -0,0 +1,22 @@
+package repl
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestPrompts(t *testing.T) {
+	color.NoColor = true
+
+	prompt, continuation := DefaultOptions().prompts()
+	if prompt != PROMPT || continuation != CONTINUATION {
+		t.Errorf("wrong default prompts, got %q and %q", prompt, continuation)
+	}
+
+	options := Options{Prompt: "vanvo> ", Continuation: "    "}
+	prompt, continuation = options.prompts()
+	if prompt != "vanvo> " || continuation != "    " {
+		t.Errorf("prompts are not overridden, got %q and %q", prompt, continuation)
+	}
+}
@@ -1,25 +1,56 @@
 package cmd
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"vanvo/cmd/repl"
+	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/evaluator"
 	"vanvo/pkg/object"
 )
 
 func errRecover() {
 	if r := recover(); r != nil {
-		fmt.Print("Lỗi trình thông dịch")
+		fmt.Print(errorhandler.InterpreterError.In(errorhandler.DefaultLocale()))
 	}
 }
 
+var (
+	teaching  = flag.Bool("hoc", false, "Chế độ học: đưa ra gợi ý cho người mới học")
+	strict    = flag.Bool("nghiem", false, "Chế độ nghiêm ngặt: báo lỗi khi gán cho biến chưa khai báo")
+	printLast = flag.Bool("giatri", false, "In giá trị của câu lệnh cuối cùng khi chạy file, giống như REPL")
+	validate  = flag.Bool("kiemtra", false, "Kiểm tra các biểu thức hằng như 1/0 trước khi chạy")
+	maxLoops  = flag.Int("gioihan", evaluator.DefaultMaxIterations, "Số lần lặp tối đa của vòng lặp 'khi' và khi duyệt tập vô hạn, 0 là không giới hạn")
+
+	prompt       = flag.String("nhac", repl.PROMPT, "Dấu nhắc của REPL")
+	continuation = flag.String("nhac-tiep", repl.CONTINUATION, "Dấu nhắc của REPL khi đang viết tiếp một khối lệnh")
+	quiet        = flag.Bool("quiet", false, "Không hiện lời chào khi mở REPL")
+)
+
+func newReplOptions() repl.Options {
+	options := repl.DefaultOptions()
+	options.Prompt = *prompt
+	options.Continuation = *continuation
+	options.Quiet = *quiet
+	return options
+}
+
+func newConfig() *evaluator.Config {
+	config := evaluator.NewConfig()
+	config.Teaching = *teaching
+	config.Strict = *strict
+	config.Validate = *validate
+	config.MaxIterations = *maxLoops
+	return config
+}
+
 func runFromFile() {
-	path, err := filepath.Abs(os.Args[1])
+	path, err := filepath.Abs(flag.Arg(0))
 	if err != nil {
-		fmt.Println("đường dẫn không hợp lệ")
+		fmt.Println(errorhandler.InvalidPath.In(errorhandler.DefaultLocale()))
 	}
 
 	file, err := os.ReadFile(path)
@@ -28,15 +59,19 @@ func runFromFile() {
 	input = strings.ReplaceAll(input, "\t", spaces)
 
 	if err != nil {
-		fmt.Printf("Không thể mở file: '%s'\n", path)
+		fmt.Print(errorhandler.CannotOpenFile.With(path).In(errorhandler.DefaultLocale()))
 	} else {
 		env := object.NewEnvironment()
+		config := newConfig()
 
-		_, errors := evaluator.EvalFromInput(input, path, env)
+		value, errors := evaluator.EvalFromInput(input, path, env, config)
 
+		fmt.Fprint(config.Stdout, errors.WarningString())
 		if errors.NotEmpty() {
-			fmt.Print(errors)
+			fmt.Fprint(config.Stdout, errors)
 
+		} else if *printLast && value != evaluator.NO_PRINT {
+			fmt.Fprintln(config.Stdout, value.Display())
 		}
 	}
 }
@@ -44,12 +79,13 @@ func runFromFile() {
 func Execute() {
 	defer errRecover()
 	initConfig()
+	flag.Parse()
 
-	if len(os.Args) > 1 {
+	if flag.NArg() > 0 {
 		runFromFile()
 
 	} else {
-		repl.Start()
+		repl.Start(newConfig(), newReplOptions())
 	}
 
 }
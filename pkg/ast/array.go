@@ -0,0 +1,36 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+	"vanvo/pkg/token"
+)
+
+type Array struct {
+	LeftBracket  token.Token
+	RightBracket token.Token
+	Data         []Expression
+}
+
+func (arr *Array) FromToken() token.Token {
+	return arr.LeftBracket
+}
+
+func (arr *Array) ToToken() token.Token {
+	return arr.RightBracket
+}
+
+func (arr *Array) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, each := range arr.Data {
+		elements = append(elements, each.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
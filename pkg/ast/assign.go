@@ -28,3 +28,21 @@ func (as *AssignStatement) String() string {
 
 	return out.String()
 }
+
+// IndexAssignStatement changes one element: xs[0] = 1
+type IndexAssignStatement struct {
+	Target *IndexExpression
+	Value  Expression
+}
+
+func (ias *IndexAssignStatement) FromToken() token.Token {
+	return ias.Target.FromToken()
+}
+
+func (ias *IndexAssignStatement) ToToken() token.Token {
+	return ias.Value.ToToken()
+}
+
+func (ias *IndexAssignStatement) String() string {
+	return ias.Target.String() + " = " + ias.Value.String()
+}
@@ -26,3 +26,33 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong. Got=%q", program.String())
 	}
 }
+
+func TestInspect(t *testing.T) {
+	one := &Int{Token: token.Token{Type: token.Int, Literal: []rune("1")}}
+	two := &Int{Token: token.Token{Type: token.Int, Literal: []rune("2")}}
+	sum := &InfixExpression{Left: one, Operator: token.Token{Type: token.Plus, Literal: []rune("+")}, Right: two}
+	program := &Program{Statements: []Statement{
+		&OutputStatement{Values: []Expression{sum}},
+		&BreakStatement{},
+	}}
+
+	visited := []Node{}
+	Inspect(program, func(node Node) bool {
+		visited = append(visited, node)
+		return true
+	})
+	if len(visited) != 6 || visited[2] != sum || visited[3] != one || visited[4] != two {
+		t.Errorf("Inspect visited wrong nodes: %v", visited)
+	}
+
+	// returning false skips the children
+	count := 0
+	Inspect(program, func(node Node) bool {
+		count++
+		_, isInfix := node.(*InfixExpression)
+		return !isInfix
+	})
+	if count != 4 {
+		t.Errorf("Inspect should skip the children of the infix, visited %d nodes", count)
+	}
+}
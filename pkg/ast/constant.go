@@ -33,6 +33,18 @@ func (i *Real) String() string {
 	return string(i.Token.Literal)
 }
 
+// Imaginary is a literal like 3i or 2.5i, Value is the coefficient
+type Imaginary struct {
+	Token token.Token
+	Value Expression
+}
+
+func (im *Imaginary) FromToken() token.Token { return im.Token }
+func (im *Imaginary) ToToken() token.Token   { return im.Token }
+func (im *Imaginary) String() string {
+	return string(im.Token.Literal)
+}
+
 type Boolean struct {
 	Token token.Token
 	Value bool
@@ -4,6 +4,7 @@ import "vanvo/pkg/token"
 
 type ForStatement struct {
 	Token      token.Token
+	Label      *Identifier
 	Conditions []Expression
 	Body       *BlockStatement
 }
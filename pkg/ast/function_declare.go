@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"bytes"
+	"strings"
 	"vanvo/pkg/token"
 )
 
@@ -22,3 +24,36 @@ func (fn *FunctionDeclareStatement) ToToken() token.Token {
 func (fn *FunctionDeclareStatement) String() string {
 	return ""
 }
+
+// FunctionLiteral is a function without a name like hàm(x) = x^2, it can be
+// passed around as any other value
+type FunctionLiteral struct {
+	Token  token.Token
+	Params []*Identifier
+	Body   Expression
+}
+
+func (fl *FunctionLiteral) FromToken() token.Token {
+	return fl.Token
+}
+
+func (fl *FunctionLiteral) ToToken() token.Token {
+	return fl.Body.ToToken()
+}
+
+func (fl *FunctionLiteral) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, param := range fl.Params {
+		params = append(params, param.String())
+	}
+
+	out.WriteString(string(fl.Token.Literal))
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") = ")
+	out.WriteString(fl.Body.String())
+
+	return out.String()
+}
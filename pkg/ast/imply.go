@@ -15,16 +15,19 @@ func (is *ImplyStatement) FromToken() token.Token {
 }
 
 func (is *ImplyStatement) ToToken() token.Token {
+	if is.Value == nil {
+		return is.Token
+	}
 	return is.Value.ToToken()
 }
 
 func (is *ImplyStatement) String() string {
 	var out bytes.Buffer
 
-	out.WriteString(string(is.Token.Literal) + " ")
+	out.WriteString(string(is.Token.Literal))
 
 	if is.Value != nil {
-		out.WriteString(is.Value.String())
+		out.WriteString(" " + is.Value.String())
 	}
 
 	return out.String()
@@ -0,0 +1,138 @@
+package ast
+
+import "reflect"
+
+// Inspect calls f for node, then for each of its children in the order they
+// are written as long as f returns true, like go/ast.Inspect
+func Inspect(node Node, f func(Node) bool) {
+	if isNil(node) || !f(node) {
+		return
+	}
+	for _, child := range children(node) {
+		Inspect(child, f)
+	}
+}
+
+func children(node Node) []Node {
+	nodes := []Node{}
+	add := func(children ...Node) {
+		for _, child := range children {
+			if !isNil(child) {
+				nodes = append(nodes, child)
+			}
+		}
+	}
+	addExpressions := func(exps []Expression) {
+		for _, exp := range exps {
+			add(exp)
+		}
+	}
+	addStatements := func(stmts []Statement) {
+		for _, stmt := range stmts {
+			add(stmt)
+		}
+	}
+
+	switch node := node.(type) {
+	case *Program:
+		addStatements(node.Statements)
+	case *BlockStatement:
+		addStatements(node.Statements)
+	case *GroupExpression:
+		addStatements(node.Statements)
+	case *ExpressionStatement:
+		add(node.Expression)
+
+	case *PrefixExpression:
+		add(node.Right)
+	case *PostfixExpression:
+		add(node.Left)
+	case *InfixExpression:
+		add(node.Left, node.Right)
+
+	case *IfExpression:
+		add(node.Condition, node.Consequence, node.Alternative)
+	case *IfStatement:
+		for _, branch := range node.Branches {
+			add(branch)
+		}
+	case *IfBranch:
+		add(node.Condition, node.Consequence)
+	case *MatchStatement:
+		add(node.Value)
+		for _, branch := range node.Branches {
+			add(branch)
+		}
+	case *MatchBranch:
+		add(node.Pattern, node.Body)
+
+	case *ForStatement:
+		addExpressions(node.Conditions)
+		add(node.Body)
+	case *ForEachStatement:
+		addExpressions(node.Conditions)
+		add(node.Body)
+	case *WhileStatement:
+		add(node.Condition, node.Body)
+	case *RepeatStatement:
+		add(node.Count, node.Counter, node.From, node.To, node.Step, node.Body)
+	case *ImplyStatement:
+		add(node.Value)
+	case *OutputStatement:
+		addExpressions(node.Values)
+
+	case *VarDeclareStatement:
+		add(node.Ident, node.Value)
+	case *AssignStatement:
+		add(node.Ident, node.Value)
+	case *IndexAssignStatement:
+		add(node.Target, node.Value)
+	case *FunctionDeclareStatement:
+		add(node.Ident)
+		for _, param := range node.Params {
+			add(param)
+		}
+		add(node.Body)
+	case *FunctionLiteral:
+		for _, param := range node.Params {
+			add(param)
+		}
+		add(node.Body)
+	case *CallExpression:
+		add(node.Function)
+		addExpressions(node.Arguments)
+	case *IndexExpression:
+		add(node.Set, node.Index)
+
+	case *List:
+		addExpressions(node.Data)
+	case *Array:
+		addExpressions(node.Data)
+	case *ListComprehension:
+		add(node.Expression)
+		addExpressions(node.Conditions)
+	case *SetComprehension:
+		add(node.Expression)
+		addExpressions(node.Conditions)
+	case *MapComprehension:
+		add(node.Key, node.Value)
+		addExpressions(node.Conditions)
+	case *QuantifierExpression:
+		addExpressions(node.Conditions)
+		add(node.Predicate)
+	case *IntInterval:
+		add(node.Lower, node.Upper, node.Step)
+	case *RealInterval:
+		add(node.Lower, node.Upper)
+	}
+	return nodes
+}
+
+// isNil also catches a nil pointer stored in a Node, like a missing label
+func isNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+	value := reflect.ValueOf(node)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
@@ -10,6 +10,8 @@ type RealInterval struct {
 	RightBracket token.Token
 	Lower        Expression
 	Upper        Expression
+	LowerOpen    bool
+	UpperOpen    bool
 }
 
 func (r *RealInterval) FromToken() token.Token {
@@ -23,11 +25,11 @@ func (r *RealInterval) ToToken() token.Token {
 func (ri *RealInterval) String() string {
 	var out bytes.Buffer
 
-	out.WriteString("[")
+	out.WriteString(openBracket(ri.LowerOpen))
 	out.WriteString(ri.Lower.String())
 	out.WriteString(",")
 	out.WriteString(ri.Upper.String())
-	out.WriteString("]")
+	out.WriteString(closeBracket(ri.UpperOpen))
 
 	return out.String()
 }
@@ -38,6 +40,8 @@ type IntInterval struct {
 	Lower        Expression
 	Upper        Expression
 	Step         Expression
+	LowerOpen    bool
+	UpperOpen    bool
 }
 
 func (ii *IntInterval) FromToken() token.Token {
@@ -51,11 +55,25 @@ func (ii *IntInterval) ToToken() token.Token {
 func (ii *IntInterval) String() string {
 	var out bytes.Buffer
 
-	out.WriteString("[")
+	out.WriteString(openBracket(ii.LowerOpen))
 	out.WriteString(ii.Lower.String())
 	out.WriteString("..")
 	out.WriteString(ii.Upper.String())
-	out.WriteString("]")
+	out.WriteString(closeBracket(ii.UpperOpen))
 
 	return out.String()
 }
+
+func openBracket(open bool) string {
+	if open {
+		return "("
+	}
+	return "["
+}
+
+func closeBracket(open bool) string {
+	if open {
+		return ")"
+	}
+	return "]"
+}
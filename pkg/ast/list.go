@@ -40,3 +40,46 @@ func (li *ListComprehension) ToToken() token.Token {
 func (li *ListComprehension) String() string {
 	return ""
 }
+
+// SetComprehension is the set-builder notation { x : x thuộc A, x > 2 },
+// unlike ListComprehension it's evaluated at once and has no duplicates
+type SetComprehension struct {
+	LeftBrace  token.Token
+	RightBrace token.Token
+	Expression Expression
+	Conditions []Expression
+}
+
+func (sc *SetComprehension) FromToken() token.Token {
+	return sc.LeftBrace
+}
+
+func (sc *SetComprehension) ToToken() token.Token {
+	return sc.RightBrace
+}
+
+func (sc *SetComprehension) String() string {
+	return ""
+}
+
+// MapComprehension builds a map from its conditions like
+// { x: x^2 với x thuộc [1..5] }
+type MapComprehension struct {
+	LeftBrace  token.Token
+	RightBrace token.Token
+	Key        Expression
+	Value      Expression
+	Conditions []Expression
+}
+
+func (mc *MapComprehension) FromToken() token.Token {
+	return mc.LeftBrace
+}
+
+func (mc *MapComprehension) ToToken() token.Token {
+	return mc.RightBrace
+}
+
+func (mc *MapComprehension) String() string {
+	return ""
+}
@@ -0,0 +1,42 @@
+package ast
+
+import "vanvo/pkg/token"
+
+// BreakStatement is 'dừng', it stops the innermost loop or the loop named by
+// Label like 'dừng vòng ngoài'
+type BreakStatement struct {
+	Token token.Token
+	Label *Identifier
+}
+
+func (bs *BreakStatement) FromToken() token.Token { return bs.Token }
+func (bs *BreakStatement) ToToken() token.Token {
+	if bs.Label != nil {
+		return bs.Label.ToToken()
+	}
+	return bs.Token
+}
+func (bs *BreakStatement) String() string { return loopControlString(bs.Token, bs.Label) }
+
+// ContinueStatement is 'tiếp', it skips to the next iteration of the innermost
+// loop or the loop named by Label
+type ContinueStatement struct {
+	Token token.Token
+	Label *Identifier
+}
+
+func (cs *ContinueStatement) FromToken() token.Token { return cs.Token }
+func (cs *ContinueStatement) ToToken() token.Token {
+	if cs.Label != nil {
+		return cs.Label.ToToken()
+	}
+	return cs.Token
+}
+func (cs *ContinueStatement) String() string { return loopControlString(cs.Token, cs.Label) }
+
+func loopControlString(tok token.Token, label *Identifier) string {
+	if label == nil {
+		return string(tok.Literal)
+	}
+	return string(tok.Literal) + " " + label.Value
+}
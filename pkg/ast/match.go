@@ -0,0 +1,63 @@
+package ast
+
+import (
+	"bytes"
+	"vanvo/pkg/token"
+)
+
+// MatchBranch has no Pattern for the default branch '_'
+type MatchBranch struct {
+	Token   token.Token
+	Pattern Expression
+	Body    *BlockStatement
+}
+
+func (mb *MatchBranch) FromToken() token.Token {
+	return mb.Token
+}
+
+func (mb *MatchBranch) ToToken() token.Token {
+	return mb.Body.ToToken()
+}
+
+func (mb *MatchBranch) String() string {
+	var out bytes.Buffer
+
+	if mb.Pattern == nil {
+		out.WriteString("_")
+	} else {
+		out.WriteString(mb.Pattern.String())
+	}
+	out.WriteString(mb.Body.String())
+
+	return out.String()
+}
+
+type MatchStatement struct {
+	Token    token.Token
+	Value    Expression
+	Branches []*MatchBranch
+}
+
+func (ms *MatchStatement) FromToken() token.Token {
+	return ms.Token
+}
+
+func (ms *MatchStatement) ToToken() token.Token {
+	if len(ms.Branches) == 0 {
+		return ms.Value.ToToken()
+	}
+	return ms.Branches[len(ms.Branches)-1].ToToken()
+}
+
+func (ms *MatchStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(string(ms.Token.Literal) + " ")
+	out.WriteString(ms.Value.String() + ":\n")
+	for _, branch := range ms.Branches {
+		out.WriteString(branch.String() + "\n")
+	}
+
+	return out.String()
+}
@@ -29,6 +29,24 @@ func (pe *PrefixExpression) String() string {
 	return out.String()
 }
 
+// PostfixExpression is an operator written after its operand, like 5!
+type PostfixExpression struct {
+	Left     Expression
+	Operator token.Token
+}
+
+func (pe *PostfixExpression) FromToken() token.Token {
+	return pe.Left.FromToken()
+}
+
+func (pe *PostfixExpression) ToToken() token.Token {
+	return pe.Operator
+}
+
+func (pe *PostfixExpression) String() string {
+	return "(" + pe.Left.String() + string(pe.Operator.Literal) + ")"
+}
+
 type InfixExpression struct {
 	Token    token.Token
 	Left     Expression
@@ -0,0 +1,36 @@
+package ast
+
+import (
+	"bytes"
+	"vanvo/pkg/token"
+)
+
+// QuantifierExpression is 'với mọi x thuộc A, P(x)' or 'tồn tại x thuộc A: P(x)',
+// the conditions are the same as the ones of 'với mỗi'
+type QuantifierExpression struct {
+	Token      token.Token
+	Conditions []Expression
+	Predicate  Expression
+}
+
+func (qe *QuantifierExpression) FromToken() token.Token {
+	return qe.Token
+}
+
+func (qe *QuantifierExpression) ToToken() token.Token {
+	return qe.Predicate.ToToken()
+}
+
+func (qe *QuantifierExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(string(qe.Token.Literal))
+	out.WriteString(" ")
+	for _, cond := range qe.Conditions {
+		out.WriteString(cond.String())
+		out.WriteString(", ")
+	}
+	out.WriteString(qe.Predicate.String())
+
+	return out.String()
+}
@@ -0,0 +1,28 @@
+package ast
+
+import "vanvo/pkg/token"
+
+// RepeatStatement runs its body a number of times: lặp 5 lần: ...
+// or with a counter: lặp i từ 1 đến 10 bước 2: ...
+type RepeatStatement struct {
+	Token token.Token
+	Label *Identifier
+	Count Expression
+
+	Counter *Identifier
+	From    Expression
+	To      Expression
+	Step    Expression
+
+	Body *BlockStatement
+}
+
+func (rs *RepeatStatement) FromToken() token.Token {
+	return rs.Token
+}
+
+func (rs *RepeatStatement) ToToken() token.Token {
+	return rs.Body.ToToken()
+}
+
+func (rs *RepeatStatement) String() string { return "" }
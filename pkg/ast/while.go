@@ -0,0 +1,21 @@
+package ast
+
+import "vanvo/pkg/token"
+
+// WhileStatement runs its body as long as the condition holds: khi n > 0: ...
+type WhileStatement struct {
+	Token     token.Token
+	Label     *Identifier
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) FromToken() token.Token {
+	return ws.Token
+}
+
+func (ws *WhileStatement) ToToken() token.Token {
+	return ws.Body.ToToken()
+}
+
+func (ws *WhileStatement) String() string { return "" }
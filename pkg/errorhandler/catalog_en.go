@@ -0,0 +1,151 @@
+package errorhandler
+
+// english translates every message of the Vietnamese catalog
+var english = map[MessageID]string{
+	// headings
+	SyntaxErrorHeading:  "Syntax error",
+	RuntimeErrorHeading: "Error",
+	WarningHeading:      "Warning",
+	Position:            " (line %d, column %d)\n",
+	InterpreterError:    "Interpreter error",
+	InvalidPath:         "Invalid path",
+	CannotOpenFile:      "Cannot open the file: '%s'\n",
+
+	// lexer
+	InvalidCharacter:    "Invalid character '%s'",
+	UnterminatedString:  "Missing the closing \" of a string",
+	InvalidEscape:       "Invalid escape character '\\%s'",
+	InvalidUnicode:      "Invalid Unicode code point '%s'",
+	MissingExponent:     "Missing the exponent after '%s'",
+	UnterminatedComment: "Missing '%s' to close the comment",
+
+	// parser
+	InvalidSyntax:         "Invalid syntax",
+	InvalidIndent:         "Invalid indentation",
+	MissingToken:          "Missing '%s'",
+	UnclosedParen:         "This '(' is never closed by ')'",
+	ExpectedToken:         "Expected '%s' instead of '%s'",
+	LetWithoutIdent:       "'cho' must be followed by an identifier",
+	DuplicateParam:        "Parameter '%s' is repeated",
+	MissingMatchBranch:    "Missing the branches of 'khớp'",
+	MisplacedLabel:        "Label '%s' can only be put before a loop",
+	MissingConsequence:    "Missing the statement after the condition",
+	UnknownPrefix:         "Unknown prefix operator",
+	UnknownInfix:          "Unknown infix operator",
+	MissingRightOperand:   "Missing the right operand of %s",
+	InvalidBasedInt:       "'%s' is not a valid base %d integer",
+	InvalidInt:            "Cannot parse this integer",
+	ManyDecimalPoints:     "'%s' has more than one decimal point",
+	InvalidReal:           "Cannot parse this real number",
+	MisplacedSeparator:    "'_' in '%s' must be between two digits",
+	OpenIntervalEndpoints: "An open interval only has two endpoints",
+
+	// names and control flow
+	NotDefined:       "'%s' is not defined",
+	AlreadyDefined:   "'%s' is already defined",
+	CannotAssign:     "Cannot assign a value to '%s'",
+	NotDeclared:      "Variable '%s' is not declared, use 'cho %s = ...'",
+	OnlyInFunction:   "'%s' can only be used inside a function",
+	OnlyInLoop:       "'%s' can only be used inside a loop",
+	UnknownLoopLabel: "No loop named '%s' encloses '%s'",
+	InvalidCondition: "Cannot use '%s' as a condition",
+
+	// loops
+	UncountableLoopSet: "The right side of 'thuộc' must be a countable set",
+	InvalidRepeatCount: "The repeat count must be a non-negative integer instead of '%s'",
+	InvalidLoopStart:   "The start of the loop must be a number",
+	InvalidLoopEnd:     "The end of the loop must be a number",
+	InvalidLoopStep:    "The step of the loop must be a number",
+	ZeroLoopStep:       "The step of the loop must not be 0",
+	TooManyIterations:  "The loop ran more than %d times, it may never stop",
+
+	// functions
+	InvalidExpression:     "Invalid expression",
+	FunctionArgumentCount: "'%s' takes %d arguments instead of %d",
+	ArgumentCount:         "Expected %d arguments instead of %d",
+	SeriesArgumentCount:   "Expected 4 arguments instead of %d, for example %s(i, 1, n, i^2)",
+	SeriesIndex:           "The first argument of '%s' must be the name of the index",
+	SeriesLower:           "The lower bound of '%s' must be a number",
+	SeriesUpper:           "The upper bound of '%s' must be a number",
+	SeriesTerm:            "The expression in '%s' must be a number instead of '%s'",
+	TooManyTerms:          "'%s' has more than %d terms, too many to compute",
+	InfiniteSeries:        "'%s' over an infinite set never finishes",
+
+	// operators
+	CannotAdd:             "Cannot add '%v' and '%v'",
+	CannotSubtract:        "Cannot subtract '%[2]v' from '%[1]v'",
+	CannotMultiply:        "Cannot multiply '%v' by '%v'",
+	CannotDivide:          "Cannot divide '%v' by '%v'",
+	CannotModulo:          "Cannot take '%v' modulo '%v'",
+	CannotCompose:         "Cannot compose '%v' with '%v'",
+	CannotPower:           "Cannot raise '%v' to the power of '%v'",
+	CannotCompare:         "Cannot compare '%v' with '%v'",
+	CannotBitwise:         "Cannot use the bitwise operator '%s' on '%v' and '%v'",
+	CannotOperate:         "Cannot use '%s' on '%v' and '%v'",
+	NegativeShift:         "The shift count must not be negative",
+	ShiftTooLarge:         "The left shift count must not be larger than %d",
+	DivideByZero:          "Cannot divide by 0",
+	CannotListUncountable: "Cannot list the elements of '%s' since it is uncountable",
+	IsWithoutType:         "The right side of 'là' must be a type name",
+	UnknownType:           "Type '%s' does not exist, the valid types are: %s",
+	InvalidBelongSet:      "The right side of 'thuộc' must be a '%s' instead of '%s'",
+	InvalidFactorial:      "Factorial is only defined for non-negative integers, not '%s'",
+	FactorialTooLarge:     "'%s' is too large for a factorial, the largest is %d!",
+	NegativeBasePower:     "Cannot raise the negative number %[2]s to the non-integer power %[1]s",
+	CannotTakeLength:      "Cannot take the length of '%v'",
+	RepeatingDecimalHint:  "%s is a repeating decimal so it stays a fraction to be exact, use số thực(%s) for its decimal value",
+	RealEqualityHint:      "Comparing reals with '%s' may fail due to rounding, use xấp xỉ(a, b)",
+
+	// sets, indices and maps
+	IndexUncountable:     "Cannot index an uncountable set",
+	CannotIndex:          "Cannot index into '%v'",
+	InvalidInfiniteIndex: "Index %v is invalid for an infinite set",
+	IndexOutOfRange:      "Index %v is out of the length %d of '%v'",
+	InvalidIndexType:     "The index must be a '%v' instead of '%v'",
+	CannotAssignElement:  "Cannot assign elements of '%v'",
+	InvalidMapKey:        "Cannot use '%s' as a key of a map",
+	MissingMapKey:        "Key %s is not in the map",
+	InvalidLowerBound:    "Cannot use '%s' as a lower bound",
+	InvalidUpperBound:    "Cannot use '%s' as an upper bound",
+	InvalidIntervalStep:  "Cannot use '%s' as a step",
+	EmptyInterval:        "Interval %s is always empty since its lower bound is greater than its upper bound",
+	ZeroIntervalStep:     "The step of an interval must not be 0",
+
+	// builtins
+	InvalidArgument:      "Cannot use '%s' as an argument",
+	InfiniteArgument:     "Cannot use an infinite set as an argument",
+	CannotTruth:          "Cannot tell whether '%s' is true or false",
+	ReplacementError:     "Error in the replacement expression: %s",
+	EmptyString:          "An empty string has no characters",
+	NotRational:          "'%s' is not a rational number with a denominator of at most %d",
+	UncountableSize:      "An uncountable set has no finite number of elements",
+	InfiniteSize:         "An infinite set has no finite number of elements",
+	DimensionMismatch:    "Both points must have the same dimension instead of %d and %d",
+	RealCoordinates:      "The coordinates of a point must be real numbers",
+	NegativeReplaceCount: "The number of replacements must not be negative",
+	ReplaceCountTooLarge: "The number of replacements must not be larger than %d",
+	CannotZipInfinite:    "Cannot pair up two infinite sets",
+}
+
+// englishTerms translates the words of the language quoted by messages, they
+// are keyed by their Vietnamese spelling
+var englishTerms = map[string]string{
+	"Số Nguyên":                     "Integer",
+	"Số Thực":                       "Real",
+	"Số Hữu Tỉ":                     "Rational",
+	"Số Phức":                       "Complex",
+	"Logic":                         "Boolean",
+	"Rỗng":                          "Null",
+	"Chuỗi":                         "String",
+	"Mảng":                          "Array",
+	"Tập Hợp":                       "Set",
+	"Từ Điển":                       "Map",
+	"Hàm":                           "Function",
+	"Lỗi":                           "Error",
+	"Giá trị trả về":                "Return value",
+	"Dừng vòng lặp":                 "Break",
+	"Tiếp vòng lặp":                 "Continue",
+	"Không thể so sánh được":        "Incomparable",
+	"Không thể thực hiện phép tính": "Inoperable",
+	"Không phải số thực":            "Not a real number",
+}
@@ -0,0 +1,129 @@
+package errorhandler
+
+// vietnamese is the language messages are written in first, every other
+// catalog translates it
+var vietnamese = map[MessageID]string{
+	// headings
+	SyntaxErrorHeading:  "Lỗi cú pháp",
+	RuntimeErrorHeading: "Lỗi",
+	WarningHeading:      "Cảnh báo",
+	Position:            " (dòng %d, cột %d)\n",
+	InterpreterError:    "Lỗi trình thông dịch",
+	InvalidPath:         "đường dẫn không hợp lệ",
+	CannotOpenFile:      "Không thể mở file: '%s'\n",
+
+	// lexer
+	InvalidCharacter:    "Ký tự '%s' không hợp lệ",
+	UnterminatedString:  "thiếu dấu \" kết thúc chuỗi",
+	InvalidEscape:       "Ký tự thoát '\\%s' không hợp lệ",
+	InvalidUnicode:      "Mã Unicode '%s' không hợp lệ",
+	MissingExponent:     "Thiếu số mũ sau '%s'",
+	UnterminatedComment: "Thiếu '%s' để kết thúc chú thích",
+
+	// parser
+	InvalidSyntax:         "Cú pháp không hợp lệ",
+	InvalidIndent:         "Thụt dòng không hợp lệ",
+	MissingToken:          "Thiếu '%s'",
+	UnclosedParen:         "'(' này chưa được đóng bằng ')'",
+	ExpectedToken:         "Cần '%s' thay vì '%s'",
+	LetWithoutIdent:       "Sau 'cho' phải là một tên định danh",
+	DuplicateParam:        "Tham số '%s' bị lặp",
+	MissingMatchBranch:    "Thiếu nhánh sau 'khớp'",
+	MisplacedLabel:        "Nhãn '%s' chỉ đặt được trước một vòng lặp",
+	MissingConsequence:    "Thiếu mệnh đề sau điều kiện",
+	UnknownPrefix:         "Tiền tố không tồn tại",
+	UnknownInfix:          "toán tử trung tố không tồn tại",
+	MissingRightOperand:   "Thiếu vế phải của %s",
+	InvalidBasedInt:       "'%s' không phải số nguyên hệ %d hợp lệ",
+	InvalidInt:            "Không thể parse số nguyên này",
+	ManyDecimalPoints:     "'%s' có nhiều hơn một dấu chấm thập phân",
+	InvalidReal:           "Không thể parse số thực này",
+	MisplacedSeparator:    "Dấu '_' trong '%s' phải nằm giữa hai chữ số",
+	OpenIntervalEndpoints: "Khoảng mở chỉ có hai đầu mút",
+
+	// names and control flow
+	NotDefined:       "'%s' chưa được khởi tạo",
+	AlreadyDefined:   "'%s' đã được khởi tạo",
+	CannotAssign:     "Không thể gán giá trị cho '%s'",
+	NotDeclared:      "Biến '%s' chưa được khai báo, hãy dùng 'cho %s = ...'",
+	OnlyInFunction:   "'%s' chỉ dùng được bên trong hàm",
+	OnlyInLoop:       "'%s' chỉ dùng được bên trong vòng lặp",
+	UnknownLoopLabel: "Không có vòng lặp nào tên '%s' bao quanh '%s'",
+	InvalidCondition: "Không thể đặt '%s' làm điều kiện",
+
+	// loops
+	UncountableLoopSet: "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được",
+	InvalidRepeatCount: "Số lần lặp phải là số nguyên không âm thay vì '%s'",
+	InvalidLoopStart:   "Giá trị bắt đầu của vòng lặp phải là một số",
+	InvalidLoopEnd:     "Giá trị kết thúc của vòng lặp phải là một số",
+	InvalidLoopStep:    "Bước nhảy của vòng lặp phải là một số",
+	ZeroLoopStep:       "Bước nhảy của vòng lặp phải khác 0",
+	TooManyIterations:  "Vòng lặp chạy quá %d lần, có thể nó không bao giờ dừng",
+
+	// functions
+	InvalidExpression:     "Biểu thức không hợp lệ",
+	FunctionArgumentCount: "'%s' cần %d tham số thay vì %d",
+	ArgumentCount:         "Cần %d tham số thay vì %d",
+	SeriesArgumentCount:   "Cần 4 tham số thay vì %d, ví dụ %s(i, 1, n, i^2)",
+	SeriesIndex:           "Tham số đầu tiên của '%s' phải là tên của biến chạy",
+	SeriesLower:           "Cận dưới của '%s' phải là một số",
+	SeriesUpper:           "Cận trên của '%s' phải là một số",
+	SeriesTerm:            "Biểu thức trong '%s' phải là một số thay vì '%s'",
+	TooManyTerms:          "'%s' có hơn %d số hạng, quá nhiều để tính",
+	InfiniteSeries:        "'%s' trên một tập vô hạn không bao giờ tính xong",
+
+	// operators
+	CannotAdd:             "Không thể cộng '%v' với '%v'",
+	CannotSubtract:        "Không thể trừ '%v' với '%v'",
+	CannotMultiply:        "Không thể nhân '%v' với '%v'",
+	CannotDivide:          "Không thể chia '%v' với '%v'",
+	CannotModulo:          "Không thể chia lấy dư '%v' với '%v'",
+	CannotCompose:         "Không thể . '%v' với '%v'",
+	CannotPower:           "Không thể mũ '%v' với '%v'",
+	CannotCompare:         "Không thể so sánh '%v' với '%v'",
+	CannotBitwise:         "Không thể dùng phép toán bit '%s' cho '%v' và '%v'",
+	CannotOperate:         "Không thể dùng phép '%s' cho '%v' và '%v'",
+	NegativeShift:         "Số bit dịch không được là số âm",
+	ShiftTooLarge:         "Số bit dịch trái không được lớn hơn %d",
+	DivideByZero:          "Không thể chia cho 0",
+	CannotListUncountable: "Không thể liệt kê phần tử của '%s' vì đây là tập không đếm được",
+	IsWithoutType:         "Vế phải của 'là' phải là tên một kiểu",
+	UnknownType:           "Kiểu '%s' không tồn tại, các kiểu hợp lệ là: %s",
+	InvalidBelongSet:      "Vế phải của mệnh đề 'thuộc' phải là một '%s' thay vì '%s'",
+	InvalidFactorial:      "Chỉ tính được giai thừa của số nguyên không âm thay vì '%s'",
+	FactorialTooLarge:     "'%s' quá lớn để tính giai thừa, chỉ tính được đến %d!",
+	NegativeBasePower:     "Không thể lấy lũy thừa không nguyên %s của số âm %s",
+	CannotTakeLength:      "Không thể lấy độ dài của '%v'",
+	RepeatingDecimalHint:  "%s là số thập phân vô hạn tuần hoàn nên được giữ ở dạng phân số để tính chính xác, dùng số thực(%s) nếu cần giá trị thập phân",
+	RealEqualityHint:      "So sánh '%s' giữa các số thực có thể sai do làm tròn, hãy dùng xấp xỉ(a, b)",
+
+	// sets, indices and maps
+	IndexUncountable:     "Không thể dùng tập không đếm được để truy cập chỉ số",
+	CannotIndex:          "Không thể truy cập chỉ số vào '%v'",
+	InvalidInfiniteIndex: "Chỉ số %v không hợp lệ cho tập vô hạn",
+	IndexOutOfRange:      "Chỉ số %v vượt quá độ dài %d của '%v'",
+	InvalidIndexType:     "Chỉ số phải là một '%v' thay vì '%v'",
+	CannotAssignElement:  "Không thể gán phần tử cho '%v'",
+	InvalidMapKey:        "Không thể dùng '%s' làm khóa của từ điển",
+	MissingMapKey:        "Khóa %s không có trong từ điển",
+	InvalidLowerBound:    "Không thể dùng '%s' làm chặn dưới",
+	InvalidUpperBound:    "Không thể dùng '%s' làm chặn trên",
+	InvalidIntervalStep:  "Không thể dùng '%s' làm bước nhảy",
+	EmptyInterval:        "Khoảng %s luôn rỗng vì cận dưới lớn hơn cận trên",
+	ZeroIntervalStep:     "Bước nhảy của khoảng phải khác 0",
+
+	// builtins
+	InvalidArgument:      "Không thể dùng '%s' làm tham số",
+	InfiniteArgument:     "Không thể dùng tập vô hạn làm tham số",
+	CannotTruth:          "Không thể xét tính đúng sai của '%s'",
+	ReplacementError:     "Lỗi trong biểu thức thay thế: %s",
+	EmptyString:          "Chuỗi rỗng không có ký tự nào",
+	NotRational:          "'%s' không phải số hữu tỉ có mẫu số không quá %d",
+	UncountableSize:      "Tập không đếm được nên không có số lượng phần tử hữu hạn",
+	InfiniteSize:         "Tập vô hạn không có số lượng phần tử hữu hạn",
+	DimensionMismatch:    "Hai điểm phải có cùng số chiều thay vì %d và %d",
+	RealCoordinates:      "Tọa độ của điểm phải là số thực",
+	NegativeReplaceCount: "Số lần thay thế không được là số âm",
+	ReplaceCountTooLarge: "Số lần thay thế không được lớn hơn %d",
+	CannotZipInfinite:    "Không thể ghép cặp hai tập vô hạn",
+}
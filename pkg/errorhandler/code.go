@@ -0,0 +1,28 @@
+package errorhandler
+
+// ErrorCode identifies the kind of an error so editors and documents can
+// refer to it without parsing the message, the codes never change once given.
+// Syntax errors are E0xx, runtime errors are E1xx.
+type ErrorCode string
+
+const (
+	INVALID_CHARACTER ErrorCode = "E001"
+	UNTERMINATED      ErrorCode = "E002"
+	INVALID_NUMBER    ErrorCode = "E003"
+	UNEXPECTED_TOKEN  ErrorCode = "E004"
+	INVALID_SYNTAX    ErrorCode = "E005"
+	INVALID_INDENT    ErrorCode = "E006"
+	MISSING_OPERAND   ErrorCode = "E007"
+	DUPLICATE_PARAM   ErrorCode = "E008"
+
+	UNDEFINED_IDENT    ErrorCode = "E101"
+	ALREADY_DEFINED    ErrorCode = "E102"
+	ARGUMENT_COUNT     ErrorCode = "E103"
+	INVALID_TYPE       ErrorCode = "E104"
+	ZERO_DIVISION      ErrorCode = "E105"
+	INDEX_OUT_OF_RANGE ErrorCode = "E106"
+	INVALID_VALUE      ErrorCode = "E107"
+	ITERATION_LIMIT    ErrorCode = "E108"
+	MISPLACED          ErrorCode = "E109"
+	BUILTIN_ERROR      ErrorCode = "E110"
+)
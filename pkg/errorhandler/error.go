@@ -14,8 +14,16 @@ type ErrorType string
 const (
 	SYNTAX_ERROR  = "Lỗi cú pháp"
 	RUNTIME_ERROR = "Lỗi"
+	WARNING       = "Cảnh báo"
 )
 
+// headings name every type of error in the catalog
+var headings = map[ErrorType]MessageID{
+	SYNTAX_ERROR:  SyntaxErrorHeading,
+	RUNTIME_ERROR: RuntimeErrorHeading,
+	WARNING:       WarningHeading,
+}
+
 var (
 	red   = color.New(color.FgHiRed)
 	blue  = color.New(color.FgBlue)
@@ -25,22 +33,24 @@ var (
 
 type TokenError struct {
 	Type    ErrorType
+	Code    ErrorCode
 	Message string
 	Token   token.Token
 }
 
-func NewTokenError(errType ErrorType, message string, tok token.Token) TokenError {
-	return TokenError{Type: errType, Message: message, Token: tok}
+func NewTokenError(errType ErrorType, code ErrorCode, message string, tok token.Token) TokenError {
+	return TokenError{Type: errType, Code: code, Message: message, Token: tok}
 }
 
 type NodeError struct {
 	Type    ErrorType
+	Code    ErrorCode
 	Message string
 	Node    ast.Node
 }
 
-func NewNodeError(errType ErrorType, message string, node ast.Node) NodeError {
-	return NodeError{Type: errType, Message: message, Node: node}
+func NewNodeError(errType ErrorType, code ErrorCode, message string, node ast.Node) NodeError {
+	return NodeError{Type: errType, Code: code, Message: message, Node: node}
 }
 
 type ErrorList struct {
@@ -48,9 +58,13 @@ type ErrorList struct {
 	lines        []string
 	maxLineDigit int
 
+	// Locale is the language messages are translated to when they are added
+	Locale Locale
+
 	LexerErrors  []TokenError
 	ParserErrors []TokenError
 	EvalErrors   []NodeError
+	Warnings     []NodeError
 }
 
 func NewErrorList(input string, filepath string) *ErrorList {
@@ -60,37 +74,59 @@ func NewErrorList(input string, filepath string) *ErrorList {
 		LexerErrors:  []TokenError{},
 		ParserErrors: []TokenError{},
 		EvalErrors:   []NodeError{},
+		Warnings:     []NodeError{},
 
 		lines:        lines,
 		filepath:     filepath,
 		maxLineDigit: findNumDigit(len(lines)),
+		Locale:       DefaultLocale(),
 	}
 }
 
-func (eh *ErrorList) AddLexerError(message string, tok token.Token) {
-	err := NewTokenError(SYNTAX_ERROR, message, tok)
+func (eh *ErrorList) AddLexerError(code ErrorCode, message Message, tok token.Token) {
+	err := NewTokenError(SYNTAX_ERROR, code, message.In(eh.Locale), tok)
 	eh.LexerErrors = append(eh.LexerErrors, err)
 }
 
-func (eh *ErrorList) AddParserError(message string, tok token.Token) {
-	err := NewTokenError(SYNTAX_ERROR, message, tok)
+func (eh *ErrorList) AddParserError(code ErrorCode, message Message, tok token.Token) {
+	err := NewTokenError(SYNTAX_ERROR, code, message.In(eh.Locale), tok)
 	eh.ParserErrors = append(eh.ParserErrors, err)
 }
 
-func (eh *ErrorList) AddParserErrorImportant(message string, tok token.Token) {
-	err := NewTokenError(SYNTAX_ERROR, message, tok)
+func (eh *ErrorList) AddParserErrorImportant(code ErrorCode, message Message, tok token.Token) {
+	err := NewTokenError(SYNTAX_ERROR, code, message.In(eh.Locale), tok)
 	eh.ParserErrors = append([]TokenError{err}, eh.ParserErrors...)
 }
 
-func (eh *ErrorList) AddRuntimeError(message string, node ast.Node) {
-	err := NewNodeError(RUNTIME_ERROR, message, node)
+func (eh *ErrorList) AddRuntimeError(code ErrorCode, message Message, node ast.Node) {
+	err := NewNodeError(RUNTIME_ERROR, code, message.In(eh.Locale), node)
 	eh.EvalErrors = append(eh.EvalErrors, err)
 }
 
+// AddWarning keeps a message that doesn't stop the program
+func (eh *ErrorList) AddWarning(message Message, node ast.Node) {
+	warning := NewNodeError(WARNING, "", message.In(eh.Locale), node)
+	eh.Warnings = append(eh.Warnings, warning)
+}
+
 func (eh *ErrorList) NotEmpty() bool {
 	return len(eh.LexerErrors) > 0 || len(eh.ParserErrors) > 0 || len(eh.EvalErrors) > 0
 }
 
+// FirstMessage returns the message of the error that would be reported first
+func (eh *ErrorList) FirstMessage() string {
+	if len(eh.LexerErrors) > 0 {
+		return eh.LexerErrors[0].Message
+	}
+	if len(eh.ParserErrors) > 0 {
+		return eh.ParserErrors[0].Message
+	}
+	if len(eh.EvalErrors) > 0 {
+		return eh.EvalErrors[0].Message
+	}
+	return ""
+}
+
 func (el *ErrorList) printLine(buf *bytes.Buffer, lineNum int, showLine bool) {
 	line := el.lines[lineNum-1]
 	if showLine && el.filepath != "" {
@@ -110,9 +146,17 @@ func (el *ErrorList) underline(buf *bytes.Buffer, from, length int) {
 	}
 }
 
-func (el *ErrorList) printErrorMessage(buf *bytes.Buffer, t ErrorType, message string) {
-	red.Fprint(buf, t+": ")
-	white.Fprintln(buf, message)
+// printErrorMessage writes the heading of an error with where it starts, the
+// REPL doesn't number its lines so the position is the only hint there
+func (el *ErrorList) printErrorMessage(buf *bytes.Buffer, t ErrorType, code ErrorCode, message string, tok token.Token) {
+	heading := headings[t].In(el.Locale)
+	if code != "" {
+		red.Fprint(buf, heading, "[", code, "]: ")
+	} else {
+		red.Fprint(buf, heading+": ")
+	}
+	white.Fprint(buf, message)
+	blue.Fprint(buf, Position.With(tok.Line, tok.Column).In(el.Locale))
 }
 
 func (el *ErrorList) printTokenErrors(buf *bytes.Buffer, errors []TokenError) {
@@ -120,12 +164,16 @@ func (el *ErrorList) printTokenErrors(buf *bytes.Buffer, errors []TokenError) {
 		fromLine := max(1, err.Token.Line-1)
 		toLine := min(err.Token.Line, len(el.lines))
 
-		el.printErrorMessage(buf, err.Type, err.Message)
+		el.printErrorMessage(buf, err.Type, err.Code, err.Message, err.Token)
 
 		for i := fromLine - 1; i < toLine; i++ {
 			if i+1 == err.Token.Line {
 				el.printLine(buf, i+1, true)
-				el.underline(buf, err.Token.Column-1, max(1, len(err.Token.Literal)))
+				length := len(err.Token.Literal)
+				if err.Token.Type == token.Endline {
+					length = 1
+				}
+				el.underline(buf, err.Token.Column-1, max(1, length))
 			} else {
 				el.printLine(buf, i+1, true)
 			}
@@ -139,7 +187,7 @@ func (el *ErrorList) printTokenErrors(buf *bytes.Buffer, errors []TokenError) {
 
 func (el *ErrorList) printNodeErrors(buf *bytes.Buffer, errors []NodeError) {
 	for index, err := range errors {
-		node := el.EvalErrors[index].Node
+		node := err.Node
 		fromTok := node.FromToken()
 		toTok := node.ToToken()
 
@@ -151,7 +199,7 @@ func (el *ErrorList) printNodeErrors(buf *bytes.Buffer, errors []NodeError) {
 			showLineNoError = true
 		}
 
-		el.printErrorMessage(buf, err.Type, err.Message)
+		el.printErrorMessage(buf, err.Type, err.Code, err.Message, fromTok)
 
 		for i := fromLine - 1; i < toLine; i++ {
 			line := el.lines[i]
@@ -178,7 +226,7 @@ func (el *ErrorList) printNodeErrors(buf *bytes.Buffer, errors []NodeError) {
 			}
 		}
 		buf.WriteString("\n")
-		if index != len(el.EvalErrors)-1 {
+		if index != len(errors)-1 {
 			buf.WriteString("\n")
 		}
 	}
@@ -198,7 +246,7 @@ func (el *ErrorList) String() string {
 		el.printTokenErrors(&buf, el.LexerErrors)
 
 	} else if len(el.ParserErrors) > 0 {
-		el.printTokenErrors(&buf, el.ParserErrors[:1])
+		el.printTokenErrors(&buf, el.ParserErrors)
 
 	} else if len(el.EvalErrors) > 0 {
 		el.printNodeErrors(&buf, el.EvalErrors[:1])
@@ -206,3 +254,17 @@ func (el *ErrorList) String() string {
 
 	return buf.String()
 }
+
+func (el *ErrorList) WarningString() string {
+	var buf bytes.Buffer
+	if len(el.Warnings) == 0 {
+		return ""
+	}
+
+	if el.filepath != "" {
+		green.Fprint(&buf, "--> ", el.filepath, "\n")
+	}
+	el.printNodeErrors(&buf, el.Warnings)
+
+	return buf.String()
+}
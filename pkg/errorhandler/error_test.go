@@ -0,0 +1,107 @@
+package errorhandler
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"vanvo/pkg/token"
+
+	"github.com/fatih/color"
+)
+
+func TestErrorPosition(t *testing.T) {
+	color.NoColor = true
+
+	input := "cho a = 1\ncho b = a $ 2"
+	errors := NewErrorList(input, "")
+	errors.AddLexerError(INVALID_CHARACTER, InvalidCharacter.With("$"), token.Token{
+		Type: token.Illegal, Literal: []rune("$"), Line: 2, Column: 11,
+	})
+
+	lines := strings.Split(errors.String(), "\n")
+	expected := []string{
+		"Lỗi cú pháp[E001]: Ký tự '$' không hợp lệ (dòng 2, cột 11)",
+		"  | cho a = 1",
+		"  | cho b = a $ 2",
+		"  |           ^",
+	}
+	for ind, line := range expected {
+		if ind >= len(lines) || lines[ind] != line {
+			t.Fatalf("wrong error output. want line %d=%q, got:\n%s", ind, line, errors.String())
+		}
+	}
+}
+
+func TestEnglishLocale(t *testing.T) {
+	color.NoColor = true
+
+	input := "cho a = 1\ncho b = a $ 2"
+	errors := NewErrorList(input, "")
+	errors.Locale = English
+	errors.AddLexerError(INVALID_CHARACTER, InvalidCharacter.With("$"), token.Token{
+		Type: token.Illegal, Literal: []rune("$"), Line: 2, Column: 11,
+	})
+
+	expected := "Syntax error[E001]: Invalid character '$' (line 2, column 11)"
+	if line := strings.Split(errors.String(), "\n")[0]; line != expected {
+		t.Errorf("wrong error output. want=%q, got=%q", expected, line)
+	}
+}
+
+// typeName stands for the types of the object package, which can't be
+// imported here
+type typeName string
+
+func (t typeName) Term() string { return string(t) }
+
+func TestMessageArguments(t *testing.T) {
+	tests := []struct {
+		message    Message
+		vietnamese string
+		english    string
+	}{
+		{InvalidSyntax, "Cú pháp không hợp lệ", "Invalid syntax"},
+		{ExpectedToken.With(":", "x"), "Cần ':' thay vì 'x'", "Expected ':' instead of 'x'"},
+		// names from the program stay as they are even when they are words of
+		// a message
+		{NotDefined.With("Lỗi trình thông dịch"), "'Lỗi trình thông dịch' chưa được khởi tạo", "'Lỗi trình thông dịch' is not defined"},
+		{NotDefined.With("Chuỗi"), "'Chuỗi' chưa được khởi tạo", "'Chuỗi' is not defined"},
+		{CannotSubtract.With(typeName("Chuỗi"), typeName("Số Nguyên")),
+			"Không thể trừ 'Chuỗi' với 'Số Nguyên'", "Cannot subtract 'Integer' from 'String'"},
+		{IndexOutOfRange.With(5, 3, typeName("Mảng")),
+			"Chỉ số 5 vượt quá độ dài 3 của 'Mảng'", "Index 5 is out of the length 3 of 'Array'"},
+		{ReplacementError.With("'x' is not defined"),
+			"Lỗi trong biểu thức thay thế: 'x' is not defined", "Error in the replacement expression: 'x' is not defined"},
+	}
+
+	for _, test := range tests {
+		if got := test.message.In(Vietnamese); got != test.vietnamese {
+			t.Errorf("wrong Vietnamese message. want=%q, got=%q", test.vietnamese, got)
+		}
+		if got := test.message.In(English); got != test.english {
+			t.Errorf("wrong English message. want=%q, got=%q", test.english, got)
+		}
+	}
+}
+
+var formatVerb = regexp.MustCompile(`%(\[\d+\])?[sdvq]`)
+
+// every message should be in both catalogs with the same arguments
+func TestEnglishCatalog(t *testing.T) {
+	for id, format := range vietnamese {
+		translated, ok := english[id]
+		if !ok {
+			t.Errorf("%q has no English translation", id)
+			continue
+		}
+		want := len(formatVerb.FindAllString(format, -1))
+		if got := len(formatVerb.FindAllString(translated, -1)); got != want {
+			t.Errorf("%q should take %d arguments in English, got %d", id, want, got)
+		}
+	}
+	for id := range english {
+		if _, ok := vietnamese[id]; !ok {
+			t.Errorf("%q is translated but not in the Vietnamese catalog", id)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package errorhandler
+
+import (
+	"os"
+	"strings"
+)
+
+// Locale is the language errors are reported in, every message is looked up
+// by its ID in the catalog of the locale
+type Locale string
+
+const (
+	Vietnamese Locale = "vi"
+	English    Locale = "en"
+)
+
+// LOCALE_ENV is the environment variable choosing the language of errors,
+// like VANVO_LANG=en
+const LOCALE_ENV = "VANVO_LANG"
+
+var catalogs = map[Locale]map[MessageID]string{
+	Vietnamese: vietnamese,
+	English:    english,
+}
+
+var terms = map[Locale]map[string]string{
+	English: englishTerms,
+}
+
+// DefaultLocale reads the locale from LOCALE_ENV, Vietnamese when it is unset
+// or unknown
+func DefaultLocale() Locale {
+	lang := strings.ToLower(os.Getenv(LOCALE_ENV))
+	for locale := range catalogs {
+		if strings.HasPrefix(lang, string(locale)) {
+			return locale
+		}
+	}
+	return Vietnamese
+}
+
+// lookup finds the format of a message, falling back to Vietnamese when the
+// locale doesn't translate it
+func (loc Locale) lookup(id MessageID) string {
+	if format, ok := catalogs[loc][id]; ok {
+		return format
+	}
+	return vietnamese[id]
+}
+
+func (loc Locale) term(term Term) string {
+	word := term.Term()
+	if translated, ok := terms[loc][word]; ok {
+		return translated
+	}
+	return word
+}
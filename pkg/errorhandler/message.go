@@ -0,0 +1,171 @@
+package errorhandler
+
+import "fmt"
+
+// MessageID is the key of a message in the catalog of every language
+type MessageID string
+
+// Message is what an error says, it's written in the locale of the error list
+// when the error is added
+type Message interface {
+	In(loc Locale) string
+}
+
+// In looks the message up in the catalog of the locale
+func (id MessageID) In(loc Locale) string {
+	return loc.lookup(id)
+}
+
+// With fills the verbs of the message. Arguments are written as they are so
+// names from the program are never translated, only Terms are
+func (id MessageID) With(args ...interface{}) Message {
+	return formattedMessage{id: id, args: args}
+}
+
+type formattedMessage struct {
+	id   MessageID
+	args []interface{}
+}
+
+func (msg formattedMessage) In(loc Locale) string {
+	args := make([]interface{}, len(msg.args))
+	for ind, arg := range msg.args {
+		if term, ok := arg.(Term); ok {
+			arg = loc.term(term)
+		}
+		args[ind] = arg
+	}
+	return fmt.Sprintf(loc.lookup(msg.id), args...)
+}
+
+// Term is an argument that is a word of the language itself, like the name
+// of a type, so it's translated along with the message
+type Term interface {
+	Term() string
+}
+
+const (
+	// headings
+	SyntaxErrorHeading  MessageID = "syntax_error"
+	RuntimeErrorHeading MessageID = "runtime_error"
+	WarningHeading      MessageID = "warning"
+	Position            MessageID = "position"
+	InterpreterError    MessageID = "interpreter_error"
+	InvalidPath         MessageID = "invalid_path"
+	CannotOpenFile      MessageID = "cannot_open_file"
+
+	// lexer
+	InvalidCharacter    MessageID = "invalid_character"
+	UnterminatedString  MessageID = "unterminated_string"
+	InvalidEscape       MessageID = "invalid_escape"
+	InvalidUnicode      MessageID = "invalid_unicode"
+	MissingExponent     MessageID = "missing_exponent"
+	UnterminatedComment MessageID = "unterminated_comment"
+
+	// parser
+	InvalidSyntax         MessageID = "invalid_syntax"
+	InvalidIndent         MessageID = "invalid_indent"
+	MissingToken          MessageID = "missing_token"
+	UnclosedParen         MessageID = "unclosed_paren"
+	ExpectedToken         MessageID = "expected_token"
+	LetWithoutIdent       MessageID = "let_without_ident"
+	DuplicateParam        MessageID = "duplicate_param"
+	MissingMatchBranch    MessageID = "missing_match_branch"
+	MisplacedLabel        MessageID = "misplaced_label"
+	MissingConsequence    MessageID = "missing_consequence"
+	UnknownPrefix         MessageID = "unknown_prefix"
+	UnknownInfix          MessageID = "unknown_infix"
+	MissingRightOperand   MessageID = "missing_right_operand"
+	InvalidBasedInt       MessageID = "invalid_based_int"
+	InvalidInt            MessageID = "invalid_int"
+	ManyDecimalPoints     MessageID = "many_decimal_points"
+	InvalidReal           MessageID = "invalid_real"
+	MisplacedSeparator    MessageID = "misplaced_separator"
+	OpenIntervalEndpoints MessageID = "open_interval_endpoints"
+
+	// names and control flow
+	NotDefined       MessageID = "not_defined"
+	AlreadyDefined   MessageID = "already_defined"
+	CannotAssign     MessageID = "cannot_assign"
+	NotDeclared      MessageID = "not_declared"
+	OnlyInFunction   MessageID = "only_in_function"
+	OnlyInLoop       MessageID = "only_in_loop"
+	UnknownLoopLabel MessageID = "unknown_loop_label"
+	InvalidCondition MessageID = "invalid_condition"
+
+	// loops
+	UncountableLoopSet MessageID = "uncountable_loop_set"
+	InvalidRepeatCount MessageID = "invalid_repeat_count"
+	InvalidLoopStart   MessageID = "invalid_loop_start"
+	InvalidLoopEnd     MessageID = "invalid_loop_end"
+	InvalidLoopStep    MessageID = "invalid_loop_step"
+	ZeroLoopStep       MessageID = "zero_loop_step"
+	TooManyIterations  MessageID = "too_many_iterations"
+
+	// functions
+	InvalidExpression     MessageID = "invalid_expression"
+	FunctionArgumentCount MessageID = "function_argument_count"
+	ArgumentCount         MessageID = "argument_count"
+	SeriesArgumentCount   MessageID = "series_argument_count"
+	SeriesIndex           MessageID = "series_index"
+	SeriesLower           MessageID = "series_lower"
+	SeriesUpper           MessageID = "series_upper"
+	SeriesTerm            MessageID = "series_term"
+	TooManyTerms          MessageID = "too_many_terms"
+	InfiniteSeries        MessageID = "infinite_series"
+
+	// operators
+	CannotAdd             MessageID = "cannot_add"
+	CannotSubtract        MessageID = "cannot_subtract"
+	CannotMultiply        MessageID = "cannot_multiply"
+	CannotDivide          MessageID = "cannot_divide"
+	CannotModulo          MessageID = "cannot_modulo"
+	CannotCompose         MessageID = "cannot_compose"
+	CannotPower           MessageID = "cannot_power"
+	CannotCompare         MessageID = "cannot_compare"
+	CannotBitwise         MessageID = "cannot_bitwise"
+	CannotOperate         MessageID = "cannot_operate"
+	NegativeShift         MessageID = "negative_shift"
+	ShiftTooLarge         MessageID = "shift_too_large"
+	DivideByZero          MessageID = "divide_by_zero"
+	CannotListUncountable MessageID = "cannot_list_uncountable"
+	IsWithoutType         MessageID = "is_without_type"
+	UnknownType           MessageID = "unknown_type"
+	InvalidBelongSet      MessageID = "invalid_belong_set"
+	InvalidFactorial      MessageID = "invalid_factorial"
+	FactorialTooLarge     MessageID = "factorial_too_large"
+	NegativeBasePower     MessageID = "negative_base_power"
+	CannotTakeLength      MessageID = "cannot_take_length"
+	RepeatingDecimalHint  MessageID = "repeating_decimal_hint"
+	RealEqualityHint      MessageID = "real_equality_hint"
+
+	// sets, indices and maps
+	IndexUncountable     MessageID = "index_uncountable"
+	CannotIndex          MessageID = "cannot_index"
+	InvalidInfiniteIndex MessageID = "invalid_infinite_index"
+	IndexOutOfRange      MessageID = "index_out_of_range"
+	InvalidIndexType     MessageID = "invalid_index_type"
+	CannotAssignElement  MessageID = "cannot_assign_element"
+	InvalidMapKey        MessageID = "invalid_map_key"
+	MissingMapKey        MessageID = "missing_map_key"
+	InvalidLowerBound    MessageID = "invalid_lower_bound"
+	InvalidUpperBound    MessageID = "invalid_upper_bound"
+	InvalidIntervalStep  MessageID = "invalid_interval_step"
+	EmptyInterval        MessageID = "empty_interval"
+	ZeroIntervalStep     MessageID = "zero_interval_step"
+
+	// builtins
+	InvalidArgument      MessageID = "invalid_argument"
+	InfiniteArgument     MessageID = "infinite_argument"
+	CannotTruth          MessageID = "cannot_truth"
+	ReplacementError     MessageID = "replacement_error"
+	EmptyString          MessageID = "empty_string"
+	NotRational          MessageID = "not_rational"
+	UncountableSize      MessageID = "uncountable_size"
+	InfiniteSize         MessageID = "infinite_size"
+	DimensionMismatch    MessageID = "dimension_mismatch"
+	RealCoordinates      MessageID = "real_coordinates"
+	NegativeReplaceCount MessageID = "negative_replace_count"
+	ReplaceCountTooLarge MessageID = "replace_count_too_large"
+	CannotZipInfinite    MessageID = "cannot_zip_infinite"
+)
@@ -1,8 +1,8 @@
 package evaluator
 
 import (
-	"fmt"
 	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/object"
 )
 
@@ -14,8 +14,8 @@ func (ev *Evaluator) evalVarDeclare(node *ast.VarDeclareStatement) object.Object
 	}
 
 	if _, ok := ev.Env.GetInScope(node.Ident.Value); ok {
-		errMsg := fmt.Sprintf("'%s' đã được khởi tạo", node.Ident.Value)
-		ev.runtimeError(errMsg)
+		errMsg := errorhandler.AlreadyDefined.With(node.Ident.Value)
+		ev.runtimeError(errorhandler.ALREADY_DEFINED, errMsg)
 	}
 	ev.Env.SetInScope(node.Ident.Value, val)
 
@@ -26,19 +26,54 @@ func (ev *Evaluator) evalFunctionDeclare(node *ast.FunctionDeclareStatement) {
 
 	params := node.Params
 	body := node.Body
-	fn := &object.Function{Ident: node.Ident, Params: params, Body: body}
+	fn := &object.Function{Ident: node.Ident, Params: params, Body: body, Env: ev.Env}
 	if _, ok := ev.Env.GetInScope(node.Ident.Value); ok {
-		errMsg := fmt.Sprintf("'%s' đã được khởi tạo", node.Ident.Value)
-		ev.runtimeError(errMsg)
+		errMsg := errorhandler.AlreadyDefined.With(node.Ident.Value)
+		ev.runtimeError(errorhandler.ALREADY_DEFINED, errMsg)
 	}
 	ev.Env.SetInScope(node.Ident.Value, fn)
 }
 
+// evalIndexAssign changes an element of an array, tuple or map in place,
+// every name bound to it sees the change
+func (ev *Evaluator) evalIndexAssign(node *ast.IndexAssignStatement) object.Object {
+	set := ev.Eval(node.Target.Set)
+	index := ev.Eval(node.Target.Index)
+	val := ev.Eval(node.Value)
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+
+	if m, ok := set.(*object.Map); ok {
+		key, ok := object.AsHashable(index)
+		if !ok {
+			errMsg := errorhandler.InvalidMapKey.With(index.Type())
+			return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, node.Target.Index)
+		}
+		return m.Set(key, val)
+	}
+
+	mutable, ok := set.(object.Mutable)
+	if !ok {
+		errMsg := errorhandler.CannotAssignElement.With(set.Type())
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, node.Target)
+	}
+
+	result := ev.atIndex(mutable, index, func(i int) object.Object {
+		return mutable.SetAt(i, val)
+	})
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+	return result
+}
+
 func (ev *Evaluator) evalAssignStatement(node *ast.AssignStatement) object.Object {
 
-	if _, ok := object.Builtins[node.Ident.Value]; ok {
-		errMsg := fmt.Sprintf("Không thể gán giá trị cho '%s'", node.Ident.Value)
-		return ev.runtimeError(errMsg)
+	// a builtin can only be hidden by declaring the name again with 'cho'
+	if obj, _ := ev.Env.Get(node.Ident.Value); obj == object.Builtins[node.Ident.Value] && obj != nil {
+		errMsg := errorhandler.CannotAssign.With(node.Ident.Value)
+		return ev.runtimeError(errorhandler.ALREADY_DEFINED, errMsg)
 	}
 
 	val := ev.Eval(node.Value)
@@ -48,8 +83,12 @@ func (ev *Evaluator) evalAssignStatement(node *ast.AssignStatement) object.Objec
 
 	obj := ev.Env.Set(node.Ident.Value, val)
 	if obj == nil {
-		errMsg := fmt.Sprintf("'%s' chưa được khai tạo", node.Ident.Value)
-		return ev.runtimeError(errMsg)
+		// strict mode catches typos, otherwise assigning declares the name
+		if ev.Config.Strict {
+			errMsg := errorhandler.NotDeclared.With(node.Ident.Value, node.Ident.Value)
+			return ev.runtimeError(errorhandler.UNDEFINED_IDENT, errMsg)
+		}
+		ev.Env.SetInScope(node.Ident.Value, val)
 	}
 
 	return val
@@ -0,0 +1,104 @@
+package evaluator
+
+import (
+	"vanvo/pkg/errorhandler"
+	"vanvo/pkg/object"
+)
+
+// Builtins that need the lexer, parser or evaluator itself, they can't live
+// in the object package.
+var evaluatorBuiltins = map[string]*object.Function{
+	"thay thế": {Evaluating: substituteBuiltin},
+	"tất cả":   {Builtin: allBuiltin},
+	"bất kỳ":   {Builtin: anyBuiltin},
+}
+
+func init() {
+	for name, fn := range evaluatorBuiltins {
+		object.Builtins[name] = fn
+	}
+}
+
+// substituteBuiltin evaluates an expression given as a string after binding
+// a name to a value, e.g. thay thế("x^2 + 1", "x", 3) gives 10. It runs with
+// the settings of the session, so 'xuất' writes where the caller's does.
+func substituteBuiltin(eval object.EvalSource, args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return object.NewArgumentError(3, args)
+	}
+
+	expression, ok := args[0].(*object.String)
+	if !ok {
+		return invalidArgument(args[0])
+	}
+	name, ok := args[1].(*object.String)
+	if !ok {
+		return invalidArgument(args[1])
+	}
+
+	env := object.NewEnvironment()
+	env.SetInScope(name.Value, args[2])
+
+	value, errors := eval(expression.Value, env)
+	if errors.NotEmpty() {
+		return object.NewError(errorhandler.ReplacementError.With(errors.FirstMessage()))
+	}
+	if value == NO_PRINT {
+		return NULL
+	}
+	return value
+}
+
+// allBuiltin tells if every element of a collection is true, elements which
+// aren't booleans follow the same rule as conditions of 'nếu', so tất cả({1, 0})
+// is false. It stops at the first false element and is true for empty ones.
+func allBuiltin(args ...object.Object) object.Object {
+	return foldTruthiness(false, args...)
+}
+
+// anyBuiltin tells if some element of a collection is true, it stops at the
+// first true element and is false for empty ones.
+func anyBuiltin(args ...object.Object) object.Object {
+	return foldTruthiness(true, args...)
+}
+
+// foldTruthiness iterates until an element has the truthiness stopAt, the
+// result is stopAt if such an element is found
+func foldTruthiness(stopAt bool, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return object.NewArgumentError(1, args)
+	}
+	set, ok := args[0].(interface{ Iterate(object.IterateCallback) })
+	if !ok {
+		return invalidArgument(args[0])
+	}
+	// an infinite interval may never have the element that stops it, a lazy
+	// set is bounded by the iteration limit of its generator instead
+	if countable, ok := args[0].(object.Set); ok {
+		if !countable.IsCountable() {
+			return invalidArgument(args[0])
+		}
+		if object.IsInfinite(countable) {
+			return object.NewError(errorhandler.InfiniteArgument)
+		}
+	}
+
+	var result object.Object = boolRef(!stopAt)
+	set.Iterate(func(element object.Object) object.Object {
+		value, ok := truthiness(element)
+		if !ok {
+			errMsg := errorhandler.CannotTruth.With(element.Type())
+			result = object.NewError(errMsg)
+		} else if value == stopAt {
+			result = boolRef(stopAt)
+		} else {
+			return element
+		}
+		return &object.LoopControl{Kind: object.BREAK_OBJ}
+	})
+	return result
+}
+
+func invalidArgument(arg object.Object) *object.Error {
+	return object.NewError(errorhandler.InvalidArgument.With(arg.Type()))
+}
@@ -2,12 +2,15 @@ package evaluator
 
 import (
 	"fmt"
+	"io"
 	"math/big"
+	"os"
 	"vanvo/pkg/ast"
 	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/lexer"
 	"vanvo/pkg/object"
 	"vanvo/pkg/parser"
+	"vanvo/pkg/token"
 )
 
 var (
@@ -15,13 +18,14 @@ var (
 	TRUE         = object.TRUE
 	FALSE        = object.FALSE
 	INCOMPARABLE = object.INCOMPARABLE
-	NO_PRINT     = &object.Null{}
+	NO_PRINT     = object.NO_PRINT
 )
 
 func EvalFromInput(
 	input string,
 	path string,
 	env *object.Environment,
+	configs ...*Config,
 ) (object.Object, *errorhandler.ErrorList) {
 
 	errors := errorhandler.NewErrorList(input, path)
@@ -29,35 +33,96 @@ func EvalFromInput(
 	l := lexer.New(input, errors)
 	p := parser.New(l, errors)
 	ev := New(env, errors)
+	if len(configs) > 0 {
+		ev.Config = configs[0]
+	}
 
 	program := p.ParseProgram()
+	if ev.Config.Validate && !errors.NotEmpty() {
+		ev.validate(program)
+	}
+	if errors.NotEmpty() {
+		return NULL, errors
+	}
 
 	value := ev.Eval(program)
 
 	return value, errors
 }
 
+// evalSource runs a program from a string with the config of ev
+func (ev *Evaluator) evalSource(input string, env *object.Environment) (object.Object, *errorhandler.ErrorList) {
+	return EvalFromInput(input, "", env, ev.Config)
+}
+
 func New(env *object.Environment, errors *errorhandler.ErrorList) *Evaluator {
-	ev := &Evaluator{Errors: errors, Env: env}
+	ev := &Evaluator{Errors: errors, Env: env, Config: NewConfig()}
 	return ev
 }
 
+// Config holds the settings of a session, it's shared by every input
+// evaluated in the same REPL or file
+type Config struct {
+	// Teaching mode gives hints to beginners as warnings
+	Teaching bool
+
+	// Strict mode requires names to be declared with 'cho' before being
+	// assigned
+	Strict bool
+
+	// Validate checks constant expressions like 1/0 before running, the same
+	// checks are done again when the program runs
+	Validate bool
+
+	// MaxIterations stops condition loops and the iteration of infinite sets
+	// that run too long, it also bounds the terms of tổng and tích. 0 means
+	// no limit
+	MaxIterations int
+
+	// Stdout receives 'xuất', Stderr receives 'in_lỗi'
+	Stdout io.Writer
+	Stderr io.Writer
+
+	warned map[string]bool
+}
+
+const DefaultMaxIterations = 10_000_000
+
+func NewConfig() *Config {
+	return &Config{
+		MaxIterations: DefaultMaxIterations,
+		Stdout:        os.Stdout,
+		Stderr:        os.Stderr,
+		warned:        map[string]bool{},
+	}
+}
+
 type Evaluator struct {
 	Errors *errorhandler.ErrorList
 	Node   ast.Node
 	Env    *object.Environment
+	Config *Config
 }
 
 func (ev *Evaluator) Eval(node ast.Node, envs ...*object.Environment) object.Object {
-	env := ev.Env
+	newev := *ev
 	if len(envs) > 0 {
-		env = envs[0]
+		newev.Env = envs[0]
 	}
-	newev := New(env, ev.Errors)
 	newev.Node = node
 	return newev.evalNode()
 }
 
+// hint adds a teaching mode warning, each kind of hint is given only once
+// per session
+func (ev *Evaluator) hint(kind string, message errorhandler.Message) {
+	if !ev.Config.Teaching || ev.Config.warned[kind] {
+		return
+	}
+	ev.Config.warned[kind] = true
+	ev.Errors.AddWarning(message, ev.Node)
+}
+
 func (ev *Evaluator) evalNode() object.Object {
 	node := ev.Node
 
@@ -77,7 +142,17 @@ func (ev *Evaluator) evalNode() object.Object {
 		right := ev.Eval(node.Right)
 		return ev.evalPrefixExpression(node.Operator, right)
 
+	case *ast.PostfixExpression:
+		left := ev.Eval(node.Left)
+		return ev.evalPostfixExpression(node.Operator, left)
+
 	case *ast.InfixExpression:
+		switch node.Operator.Type {
+		case token.Is:
+			return ev.evalIs(node)
+		case token.And, token.Or:
+			return ev.evalLogical(node)
+		}
 		left := ev.Eval(node.Left)
 		right := ev.Eval(node.Right)
 		return ev.evalInfixExpression(node.Operator, left, right)
@@ -94,25 +169,49 @@ func (ev *Evaluator) evalNode() object.Object {
 	case *ast.IfStatement:
 		return ev.evalIfStatement(node)
 
+	case *ast.MatchStatement:
+		return ev.evalMatchStatement(node)
+
 	case *ast.ForStatement:
 		return ev.evalForStatement(node)
 
 	case *ast.ForEachStatement:
 		return ev.evalForEachStatement(node)
 
+	case *ast.WhileStatement:
+		return ev.evalWhileStatement(node)
+
+	case *ast.RepeatStatement:
+		return ev.evalRepeatStatement(node)
+
+	case *ast.BreakStatement:
+		return &object.LoopControl{Kind: object.BREAK_OBJ, Node: node, Label: labelName(node.Label)}
+
+	case *ast.ContinueStatement:
+		return &object.LoopControl{Kind: object.CONTINUE_OBJ, Node: node, Label: labelName(node.Label)}
+
 	case *ast.ImplyStatement:
+		if node.Value == nil {
+			return &object.Imply{Value: NULL, Node: node}
+		}
 		val := ev.Eval(node.Value)
-		return &object.Imply{Value: val}
+		return &object.Imply{Value: val, Node: node}
 
 	case *ast.AssignStatement:
 		return ev.evalAssignStatement(node)
 
+	case *ast.IndexAssignStatement:
+		return ev.evalIndexAssign(node)
+
 	case *ast.VarDeclareStatement:
 		return ev.evalVarDeclare(node)
 
 	case *ast.FunctionDeclareStatement:
 		ev.evalFunctionDeclare(node)
 
+	case *ast.FunctionLiteral:
+		return &object.Function{Params: node.Params, Body: node.Body, Env: ev.Env}
+
 	case *ast.OutputStatement:
 		ev.evalOutputStatement(node)
 
@@ -128,6 +227,11 @@ func (ev *Evaluator) evalNode() object.Object {
 	case *ast.Real:
 		return &object.Real{Value: node.Value}
 
+	case *ast.Imaginary:
+		// 2i is always imaginary, even when a variable i is defined
+		coefficient := ev.Eval(node.Value).(object.Realness)
+		return object.NewComplex(object.NewInt(big.NewInt(0)), coefficient)
+
 	case *ast.Boolean:
 		return boolRef(node.Value)
 
@@ -140,9 +244,21 @@ func (ev *Evaluator) evalNode() object.Object {
 	case *ast.List:
 		return ev.evalList(node)
 
+	case *ast.Array:
+		return ev.evalArray(node)
+
 	case *ast.ListComprehension:
 		return ev.evalListComprehension(node)
 
+	case *ast.MapComprehension:
+		return ev.evalMapComprehension(node)
+
+	case *ast.SetComprehension:
+		return ev.evalSetComprehension(node)
+
+	case *ast.QuantifierExpression:
+		return ev.evalQuantifier(node)
+
 	case *ast.IntInterval:
 		return ev.evalIntInterval(node)
 
@@ -161,7 +277,11 @@ func (ev *Evaluator) evalProgram(program *ast.Program) object.Object {
 		result = ev.Eval(statement)
 
 		if returnValue, ok := result.(*object.Imply); ok {
-			return returnValue.Value
+			errMsg := errorhandler.OnlyInFunction.With(string(returnValue.Node.FromToken().Literal))
+			return ev.runtimeError(errorhandler.MISPLACED, errMsg, returnValue.Node)
+		}
+		if isLoopControl(result) {
+			return ev.loopControlOutsideLoop(result)
 		}
 	}
 	return result
@@ -174,7 +294,7 @@ func (ev *Evaluator) evalBlockStatement(stmts []ast.Statement) object.Object {
 	for _, statement := range stmts {
 		result = ev.Eval(statement, env)
 
-		if result.Type() == object.IMPLY_OBJ {
+		if result.Type() == object.IMPLY_OBJ || isLoopControl(result) {
 			return result
 		}
 	}
@@ -185,8 +305,8 @@ func (ev *Evaluator) evalBlockStatement(stmts []ast.Statement) object.Object {
 func (ev *Evaluator) evalIdentifier(node *ast.Identifier) object.Object {
 	val, ok := ev.Env.Get(node.Value)
 	if !ok {
-		errMsg := fmt.Sprintf("'%s' chưa được khởi tạo", node.Value)
-		return ev.runtimeError(errMsg)
+		errMsg := errorhandler.NotDefined.With(node.Value)
+		return ev.runtimeError(errorhandler.UNDEFINED_IDENT, errMsg)
 	}
 
 	return val
@@ -206,39 +326,39 @@ func (ev *Evaluator) evalOutputStatement(stmt *ast.OutputStatement) {
 	for _, value := range stmt.Values {
 		evaluated := ev.Eval(value)
 		if str, isString := evaluated.(*object.String); isString {
-			fmt.Print(str.Value, " ")
+			fmt.Fprint(ev.Config.Stdout, str.Value, " ")
 		} else {
-			fmt.Print(evaluated.Display(), " ")
+			fmt.Fprint(ev.Config.Stdout, evaluated.Display(), " ")
 		}
 	}
-	fmt.Println()
+	fmt.Fprintln(ev.Config.Stdout)
 }
 
 func (ev *Evaluator) isTruthy(obj object.Object) bool {
+	value, ok := truthiness(obj)
+	if !ok {
+		errMsg := errorhandler.InvalidCondition.With(obj.Type())
+		ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
+	}
+	return value
+}
+
+// truthiness gives the boolean value of a condition, ok is false when the
+// object can't be used as a condition
+func truthiness(obj object.Object) (value bool, ok bool) {
 	switch obj := obj.(type) {
 	case *object.Null:
-		return false
+		return false, true
 	case *object.Boolean:
-		return obj.Value
+		return obj.Value, true
 	case *object.Int:
-		if obj.Value.Cmp(object.IntZero) == 0 {
-			return false
-		}
-		return true
+		return obj.Value.Cmp(object.IntZero) != 0, true
 	case *object.Real:
-		if obj.Value.Cmp(big.NewFloat(0)) == 0 {
-			return false
-		}
-		return true
+		return obj.Value.Cmp(big.NewFloat(0)) != 0, true
 	case *object.Quotient:
-		if obj.Value.Num().Cmp(object.IntZero) == 0 {
-			return false
-		}
-		return true
+		return obj.Value.Num().Cmp(object.IntZero) != 0, true
 	default:
-		errMsg := fmt.Sprintf("Không thể đặt '%s' làm điều kiện", obj.Type())
-		ev.runtimeError(errMsg)
-		return false
+		return false, false
 	}
 }
 
@@ -249,11 +369,11 @@ func boolRef(val bool) *object.Boolean {
 	return FALSE
 }
 
-func (ev *Evaluator) runtimeError(msg string, asts ...ast.Node) object.Object {
+func (ev *Evaluator) runtimeError(code errorhandler.ErrorCode, msg errorhandler.Message, asts ...ast.Node) object.Object {
 	node := ev.Node
 	if len(asts) > 0 {
 		node = asts[0]
 	}
-	ev.Errors.AddRuntimeError(msg, node)
+	ev.Errors.AddRuntimeError(code, msg, node)
 	return NULL
 }
@@ -1,87 +1,2405 @@
 package evaluator
 
-// import (
-// 	"testing"
-// 	"vila/pkg/errorhandler"
-// 	"vila/pkg/lexer"
-// 	"vila/pkg/object"
-// 	"vila/pkg/parser"
-// )
-
-// func testEval(t *testing.T, input string) object.Object {
-// 	errors := errorhandler.NewErrorList(input, "")
-// 	env := object.NewEnvironment()
-
-// 	l := lexer.New(input, errors)
-// 	p := parser.New(l, errors)
-// 	ev := New(env, errors)
-
-// 	program := p.ParseProgram()
-// 	value := ev.Eval(program)
-
-// 	if errors.NotEmpty() {
-// 		t.Fatalf("input %q has errors: \n%s", input, errors)
-// 	}
-
-// 	return value
-// }
-
-// func testIntObject(t *testing.T, obj object.Object, expected int64) {
-// 	result, ok := obj.(*object.Int)
-// 	if !ok {
-// 		t.Fatalf("object is not *object.Int. got=%#v, expected=%d", obj, expected)
-// 	}
-
-// 	if result.Value != expected {
-// 		t.Errorf("object has wrong value. want=%d, got=%d", expected, result.Value)
-// 	}
-// }
-
-// func TestEvalInteger(t *testing.T) {
-// 	tests := []struct {
-// 		input    string
-// 		expected int64
-// 	}{
-// 		{"3", 3},
-// 		{"-3", -3},
-// 		{"--3", 3},
-// 		{"-3 + 4", 1},
-// 		{"10 - 4", 6},
-// 		{"-10 * 4 - 3 + 7", -36},
-// 	}
-
-// 	for _, test := range tests {
-// 		value := testEval(t, test.input)
-// 		testIntObject(t, value, test.expected)
-// 	}
-// }
-
-// func testRealObject(t *testing.T, obj object.Object, expected float64) {
-// 	f, ok := obj.(*object.Real)
-// 	if !ok {
-// 		t.Errorf("object is not *object.Real. got=%#v", obj)
-// 		return
-// 	}
-
-// 	if f.Value != expected {
-// 		t.Errorf("object has wrong value. want=%f, got=%f", expected, f.Value)
-// 	}
-// }
-
-// func TestEvalReal(t *testing.T) {
-// 	tests := []struct {
-// 		input    string
-// 		expected float64
-// 	}{
-// 		{"3.14", 3.14},
-// 		{"--3.14", 3.14},
-// 		{"-3.14", -3.14},
-// 		{"3 + 0.14", 3.14},
-// 		{"-3 * 5 -1.59 + 6", -10.59},
-// 	}
-
-// 	for _, test := range tests {
-// 		value := testEval(t, test.input)
-// 		testRealObject(t, value, test.expected)
-// 	}
-// }
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"vanvo/pkg/errorhandler"
+	"vanvo/pkg/lexer"
+	"vanvo/pkg/object"
+	"vanvo/pkg/parser"
+)
+
+func testEval(t *testing.T, input string) object.Object {
+	errors := errorhandler.NewErrorList(input, "")
+	env := object.NewEnvironment()
+
+	l := lexer.New(input, errors)
+	p := parser.New(l, errors)
+	ev := New(env, errors)
+
+	program := p.ParseProgram()
+	value := ev.Eval(program)
+
+	if errors.NotEmpty() {
+		t.Fatalf("input %q has errors: \n%s", input, errors)
+	}
+
+	return value
+}
+
+func testDisplay(t *testing.T, input string, expected string) {
+	value := testEval(t, input)
+
+	if value.Display() != expected {
+		t.Errorf("input %q has wrong value. want=%s, got=%s", input, expected, value.Display())
+	}
+}
+
+func testError(t *testing.T, input string, expected string) {
+	testErrorWith(t, input, expected, NewConfig())
+}
+
+// testIterationError runs input with a small iteration limit so a program
+// that would never stop fails quickly
+func testIterationError(t *testing.T, input string, expected string) {
+	config := NewConfig()
+	config.MaxIterations = 100
+	testErrorWith(t, input, expected, config)
+}
+
+func testErrorWith(t *testing.T, input string, expected string, config *Config) {
+	errors := errorhandler.NewErrorList(input, "")
+	env := object.NewEnvironment()
+
+	l := lexer.New(input, errors)
+	p := parser.New(l, errors)
+	ev := New(env, errors)
+	ev.Config = config
+
+	ev.Eval(p.ParseProgram())
+
+	if len(errors.EvalErrors) == 0 {
+		t.Fatalf("input %q expected runtime error %q, got none", input, expected)
+	}
+	if errors.EvalErrors[0].Message != expected {
+		t.Errorf("input %q has wrong error. want=%q, got=%q", input, expected, errors.EvalErrors[0].Message)
+	}
+}
+
+func TestEvalInteger(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"3", "3"},
+		{"-3", "-3"},
+		{"--3", "3"},
+		{"-3 + 4", "1"},
+		{"10 - 4", "6"},
+		{"-10 * 4 - 3 + 7", "-36"},
+		{"0xFF", "255"},
+		{"0b1010", "10"},
+		{"0o17 + 0x1F", "46"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestEvalReal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"3.14", "3.14"},
+		{"--3.14", "3.14"},
+		{"-3.14", "-3.14"},
+		{"3 + 0.14", "3.14"},
+		{"-3 * 5 -1.59 + 6", "-10.59"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestEvalQuotient(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1/3 + 1/3 + 1/3", "1"},
+		{"1/3 + 1/3 + 1/3 == 1", "đúng"},
+		{"1/10 + 2/10 == 3/10", "đúng"},
+		{"4/6", "2/3"},
+		{"-10/4", "-5/2"},
+		{"2/3", "2/3"},
+		{"6/3", "2"},
+		{"1/2 + 0.25", "0.75"},
+		{"1/3 là Số Hữu Tỉ", "đúng"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestReplaceBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`thay_thế("a-b-c-d", "-", "+")`, `"a+b+c+d"`},
+		{`thay_thế("mèo mèo mèo", "mèo", "chó")`, `"chó chó chó"`},
+		{`thay_thế("a-b-c-d", "-", "+", 2)`, `"a+b+c-d"`},
+		{`thay_thế("a-b-c-d", "-", "+", 0)`, `"a-b-c-d"`},
+		{`thay_thế("abc", "x", "y")`, `"abc"`},
+		{`thay_thế("a-b-c", "-", "+", 2^62)`, `"a+b+c"`},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	// a count past int64 used to wrap around to a small or negative number
+	testError(t, `thay_thế("a-b-c", "-", "+", 2^64)`, "Số lần thay thế không được lớn hơn 9223372036854775807")
+	testError(t, `thay_thế("a-b-c", "-", "+", 2^64 + 1)`, "Số lần thay thế không được lớn hơn 9223372036854775807")
+	testError(t, `thay_thế("a-b-c", "-", "+", -1)`, "Số lần thay thế không được là số âm")
+}
+
+func TestPrefixSuffixBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`bắt_đầu_bằng("Việt Nam", "Việt")`, "đúng"},
+		{`bắt_đầu_bằng("Việt Nam", "Viet")`, "sai"},
+		{`bắt_đầu_bằng("đường", "đ")`, "đúng"},
+		{`bắt_đầu_bằng("abc", "")`, "đúng"},
+		{`kết_thúc_bằng("Hà Nội", "Nội")`, "đúng"},
+		{`kết_thúc_bằng("Hà Nội", "Noi")`, "sai"},
+		{`kết_thúc_bằng("phở", "ở")`, "đúng"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestStringLength(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`len("a\nb")`, "3"},
+		{`len("\t")`, "1"},
+		{`#"tiếng Việt"`, "10"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestStringConcatAndIndex(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"xin" . "chào"`, `"xinchào"`},
+		{`"a" . "b" . "c"`, `"abc"`},
+		{`"xin chào"[4]`, `"c"`},
+		{`"Việt Nam"[1]`, `"i"`},
+		{`"Việt Nam"[2]`, `"ệ"`},
+		{`cho s = "đường"; s[0] . (s[4])`, `"đg"`},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestStringErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"xin" . 1`, "Không thể . 'Chuỗi' với 'Số Nguyên'"},
+		{`"abc"[3]`, "Chỉ số 3 vượt quá độ dài 3 của 'Chuỗi'"},
+		{`"abc"[-4]`, "Chỉ số -4 vượt quá độ dài 3 của 'Chuỗi'"},
+	}
+
+	for _, test := range tests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestArray(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3]", "[1, 2, 3]"},
+		{"[5]", "[5]"},
+		{"[1, 2,]", "[1, 2]"},
+		{"[1, 2]", "[1,2]"},
+		{`[1, "hai", 3.5, đúng]`, `[1, "hai", 3.5, đúng]`},
+		{"[1, 2, 3][1]", "2"},
+		{"[1, 2, 3] + [4, 5, 6]", "[1, 2, 3, 4, 5, 6]"},
+		{"2 thuộc [1, 2, 3]", "đúng"},
+		{"cho s = 0; với mỗi x thuộc [1, 2, 3]: s = s + x\ns", "6"},
+		{"[]", "[]"},
+		{"#[]", "0"},
+		{"[[1..3], 4, 5]", "[[1..3], 4, 5]"},
+		{"[[1..3], 4, 5][0][2]", "3"},
+		{"[[1, 2, 3], [4, 5, 6], 7][1][0]", "4"},
+		{"[[1, 2], 3, 4]", "[[1,2], 3, 4]"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestSubstituteBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`thay thế("x^2 + 1", "x", 3)`, "10"},
+		{`thay thế("2x", "x", 1/3)`, "2/3"},
+		{`cho x = 100; thay thế("x + 1", "x", 1)`, "2"},
+		{`thay thế("căn(a)", "a", 16)`, "4"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, `thay thế("y + 1", "x", 1)`, "Lỗi trong biểu thức thay thế: 'y' chưa được khởi tạo")
+	testError(t, `thay thế("x +", "x", 1)`, "Lỗi trong biểu thức thay thế: Cú pháp không hợp lệ")
+	testIterationError(t, `thay thế("khi đúng: x = x + 1", "x", 1)`,
+		"Lỗi trong biểu thức thay thế: Vòng lặp chạy quá 100 lần, có thể nó không bao giờ dừng")
+
+	// the expression writes to the output of the session
+	var stdout bytes.Buffer
+	config := NewConfig()
+	config.Stdout = &stdout
+	EvalFromInput(`thay thế("xuất x + 1", "x", 2)`, "", object.NewEnvironment(), config)
+	if stdout.String() != "3 \n" {
+		t.Errorf("'xuất' in thay thế should write to the session output, got %q", stdout.String())
+	}
+}
+
+func TestAllAnyBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"tất cả([])", "đúng"},
+		{"bất kỳ([])", "sai"},
+		{"tất cả({ x : x thuộc [1..3], x > 5 })", "đúng"},
+		{"tất cả([đúng, 1, 2])", "đúng"},
+		{"tất cả({đúng, đúng})", "đúng"},
+		{"tất cả({đúng, sai, đúng})", "sai"},
+		{"bất kỳ({sai, sai})", "sai"},
+		{"bất kỳ({sai, đúng})", "đúng"},
+		{"tất cả({1, 1/2, 0.5})", "đúng"},
+		{"tất cả({1, 0, đúng})", "sai"},
+		{"bất kỳ({0, 0.0, sai, 3})", "đúng"},
+		{"tất cả({ x > 0 | x thuộc [1..10] })", "đúng"},
+		{"bất kỳ({ x % 7 == 0 | x thuộc [1..6] })", "sai"},
+		// short-circuit stops infinite sets and skips the elements left
+		{"bất kỳ({ x > 5 | x thuộc [1..] })", "đúng"},
+		{"tất cả({ x < 5 | x thuộc [1..] })", "sai"},
+		{`tất cả({sai, "a"})`, "sai"},
+		{`bất kỳ({đúng, "a"})`, "đúng"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, `tất cả({đúng, "a"})`, "Không thể xét tính đúng sai của 'Chuỗi'")
+	testError(t, "bất kỳ(1)", "Không thể dùng 'Số Nguyên' làm tham số")
+	testError(t, "tất cả([1..])", "Không thể dùng tập vô hạn làm tham số")
+	testError(t, "bất kỳ([0..])", "Không thể dùng tập vô hạn làm tham số")
+	testError(t, "tất cả([1..] hợp [-5..-1])", "Không thể dùng tập vô hạn làm tham số")
+	testError(t, "tất cả((0, 1))", "Không thể dùng 'Tập Hợp' làm tham số")
+	testIterationError(t, "tất cả({ x > 0 | x thuộc [1..] })", "Vòng lặp chạy quá 100 lần, có thể nó không bao giờ dừng")
+}
+
+func TestBooleanAlias(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"đúng", "đúng"},
+		{"sai", "sai"},
+		{"true", "đúng"},
+		{"false", "sai"},
+		{"true == đúng", "đúng"},
+		{"false == sai", "đúng"},
+		{"!true", "sai"},
+		{"nếu false: 1 còn không: 2", "2"},
+		{"true là Logic", "đúng"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestAndOr(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0 hoặc 5", "5"},
+		{"3 hoặc 5", "3"},
+		{"sai hoặc 0 hoặc 7", "7"},
+		{"0 hoặc sai hoặc 0", "0"},
+		{"2 hoặc sai hoặc 7", "2"},
+		{`0 hoặc "mặc định"`, `"mặc định"`},
+		{"0 hay 1/2", "1/2"},
+		{"3 và 4", "4"},
+		{"0 và 4", "0"},
+		{"1 và 2 và 3", "3"},
+		{"1 và 0 và 3", "0"},
+		{"đúng và sai hoặc 2", "2"},
+		{"cho x = 0\ncho y = x hoặc 10\ny", "10"},
+		{"nếu 0 hoặc sai: 1 còn không: 2", "2"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestShortCircuit(t *testing.T) {
+	counter := "cho đếm = 0\nhàm f(x):\n    đếm = đếm + 1\n    trả về x\n"
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{counter + "sai và f(đúng)\nđếm", "0"},
+		{counter + "đúng hoặc f(đúng)\nđếm", "0"},
+		{counter + "0 và f(1)\nđếm", "0"},
+		{counter + "5 hoặc f(1)\nđếm", "0"},
+		{counter + "đúng và f(sai)\nđếm", "1"},
+		{counter + "sai hoặc f(đúng)\nđếm", "1"},
+		{counter + "f(sai) và f(đúng) và f(đúng)\nđếm", "1"},
+		{counter + "f(sai) hoặc f(sai) hoặc f(đúng) hoặc f(đúng)\nđếm", "3"},
+		{counter + "nếu sai và f(đúng): 1\nđếm", "0"},
+		{"0 và 1/0", "0"},
+		{"cho xs = [1, 2, 3]\ncho i = 5\ni < len(xs) và xs[i] > 0", "sai"},
+		{"hàm g(x):\n    x > 0 hoặc (trả về -1)\n    trả về x\ng(3) + g(-3)", "2"},
+		{"sai và chưa_có", "sai"},
+		{"đúng hoặc chưa_có", "đúng"},
+		{"0 và chưa_có(1)", "0"},
+		{"nếu 1 > 2 và chưa_có > 0: 1 còn không: 2", "2"},
+		{"{ x : x thuộc [1..5], x > 0 hoặc chưa_có }", "{1, 2, 3, 4, 5}"},
+		{"sốLượng({ x : x thuộc [1..5], x > 9 và chưa_có })", "0"},
+		{"với mọi x thuộc [1..3], x > 0 hoặc chưa_có", "đúng"},
+		{"cho k = 0\nkhi k < 3 và (k >= 0 hoặc chưa_có): k = k + 1\nk", "3"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+// 'và' and 'hoặc' give back one of their operands like Python instead of a
+// Boolean, a value used as a condition is still read as true or false
+func TestLogicalOperand(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0 hoặc 5", "5"},
+		{"5 hoặc 0", "5"},
+		{"0 và 5", "0"},
+		{"5 và 0", "0"},
+		{"3 và 4", "4"},
+		{"sai hoặc 0", "0"},
+		{"0 hoặc sai hoặc [1]", "[1]"},
+		{"nếu 0 hoặc 5: 1 còn không: 2", "1"},
+		{"(0 hoặc 5) == đúng", "sai"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestIsType(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"5 là Số Nguyên", "đúng"},
+		{"5 là số nguyên", "đúng"},
+		{"5.5 là Số Nguyên", "sai"},
+		{"5.5 là Số Thực", "đúng"},
+		{"1/3 là Số Hữu Tỉ", "đúng"},
+		{"6/3 là Số Hữu Tỉ", "sai"},
+		{"(1 + I) là Số Phức", "đúng"},
+		{"đúng là Logic", "đúng"},
+		{`"a" là Chuỗi`, "đúng"},
+		{"[1, 2, 3] là Mảng", "đúng"},
+		{"{1, 2} là Tập Hợp", "đúng"},
+		{"[1..] là Tập Hợp", "đúng"},
+		{"căn là Hàm", "đúng"},
+		{"cho f(x) = x\nf là Hàm", "đúng"},
+		{"hàm f():\n    trả về\nf() là Rỗng", "đúng"},
+		{"cho x = 2\nx là số nguyên và x > 1", "đúng"},
+		{"cho x = 2\n(x + 0.5) là Số Thực", "đúng"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "5 là Số Tự Nhiên", "Kiểu 'Số Tự Nhiên' không tồn tại, các kiểu hợp lệ là: "+
+		"Số Nguyên, Số Thực, Số Hữu Tỉ, Số Phức, Logic, Chuỗi, Mảng, Tập Hợp, Từ Điển, Hàm, Rỗng")
+	testError(t, "5 là 5", "Vế phải của 'là' phải là tên một kiểu")
+	testError(t, "y là Số Nguyên", "'y' chưa được khởi tạo")
+}
+
+func TestFunctionStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hàm f(x, y):\n    cho z = x + y\n    z * 2\nf(1, 2)", "6"},
+		{"hàm một():\n    1\nmột()", "1"},
+		{"hàm giai thừa(n):\n    nếu n <= 1:\n        => 1\n    n * giai thừa(n - 1)\ngiai thừa(5)", "120"},
+		{"cho a = 1\nhàm f():\n    a\nhàm g(a):\n    f()\ng(100)", "1"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "hàm f(x, y):\n    x + y\nf(1)", "'f' cần 2 tham số thay vì 1")
+}
+
+func TestFunctionLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho f = hàm(x) = x^2\nf(5)", "25"},
+		{"(hàm(a, b) = a + b)(1, 2)", "3"},
+		{"hàm() = 1", "hàm()"},
+		{"bảng giá trị(hàm(x) = 2x, {1, 2})", "[{1, 2}, {2, 4}]"},
+		{"cho f = hàm(x) = x + 1\ncho g(x) = 2x\n(g . f)(3)", "8"},
+		// closures see later changes of the variables they capture
+		{"cho k = 3\ncho f = hàm(x) = x + k\nk = 10\nf(1)", "11"},
+		{"hàm tạo đếm():\n    cho n = 0\n    hàm() = (n = n + 1; n)\n" +
+			"cho c = tạo đếm()\nc()\nc()\nc()", "3"},
+		{"hàm tạo đếm():\n    cho n = 0\n    hàm() = (n = n + 1; n)\n" +
+			"cho a = tạo đếm()\ncho b = tạo đếm()\na(); a()\nb()", "1"},
+		{"cho gt = hàm(n) = 1 nếu n <= 1 ngược lại n * gt(n - 1)\ngt(10)", "3628800"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "(hàm(x, y) = x + y)(1)", "'hàm' cần 2 tham số thay vì 1")
+}
+
+func TestTabulateBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho bình phương(x) = x^2\nbảng giá trị(bình phương, [1..4])", "[{1, 1}, {2, 4}, {3, 9}, {4, 16}]"},
+		{"cho f(x) = x/2\nbảng giá trị(f, {1, 2, 3})", "[{1, 1/2}, {2, 1}, {3, 3/2}]"},
+		{"cho f(x) = x + 1\ncho g(x) = 2x\nbảng giá trị(f.g, {0, 1})", "[{0, 1}, {1, 3}]"},
+		{"cho f(x) = x\nbảng giá trị(f, [])", "[]"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "cho f(x) = x\nbảng giá trị(f, [1..])", "Không thể dùng tập vô hạn làm tham số")
+}
+
+func TestMapBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"ánh xạ(hàm(x) = x mod 3, {1, 2, 3, 4, 5, 6})", "{0, 1, 2}"},
+		{"ánh xạ(hàm(x) = x mod 3, [1, 2, 3, 4, 5, 6])", "[1, 2, 0, 1, 2, 0]"},
+		{"cho f(x) = x^2\nánh xạ(f, {-2, -1, 0, 1, 2})", "{0, 1, 4}"},
+		{"cho f(x) = x^2\nánh xạ(f, [-2, -1, 0, 1, 2])", "[4, 1, 0, 1, 4]"},
+		{"ánh xạ(hàm(x) = 10 - x, [1..4])", "{6, 7, 8, 9}"},
+		{"ánh xạ(hàm(x) = x, [])", "[]"},
+		{"ánh xạ(hàm(x) = x, { x : x thuộc [1..3], x > 5 })", "{}"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "ánh xạ(hàm(x) = x, [1..])", "Không thể dùng tập vô hạn làm tham số")
+	testError(t, "ánh xạ({1, 2, 3}, hàm(x) = x)", "Không thể dùng 'Tập Hợp' làm tham số")
+}
+
+func TestGroupBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"gom_nhóm(hàm(x) = x % 2, [1..6])", "{1: [1, 3, 5], 0: [2, 4, 6]}"},
+		{"gom_nhóm(hàm(x) = x % 2 == 0, [1, 2, 3, 4, 5])", "{sai: [1, 3, 5], đúng: [2, 4]}"},
+		{"hàm loại(x):\n    nếu x % 2 == 0: \"chẵn\"\n    ngược lại: \"lẻ\"\ngom_nhóm(loại, [4, 7, 10])",
+			"{\"chẵn\": [4, 10], \"lẻ\": [7]}"},
+		{"cho g = gom_nhóm(hàm(x) = x % 2, [1..6])\ng[0]", "[2, 4, 6]"},
+		{"gom_nhóm(hàm(x) = x, [])", "{}"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "gom_nhóm(hàm(x) = x % 2, [1..])", "Không thể dùng tập vô hạn làm tham số")
+	testError(t, "gom_nhóm(hàm(x) = [1..x], [1..3])", "Không thể dùng 'Tập Hợp' làm khóa của từ điển")
+}
+
+func TestZipBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"ghép_cặp([1, 2, 3], [\"a\", \"b\", \"c\"])", "[{1, \"a\"}, {2, \"b\"}, {3, \"c\"}]"},
+		{"ghép_cặp([1, 2, 3], [\"a\", \"b\", \"c\", \"d\"])", "[{1, \"a\"}, {2, \"b\"}, {3, \"c\"}]"},
+		{"ghép_cặp([1, 2, 3], [4, 5, 6, 7, 8])", "[{1, 4}, {2, 5}, {3, 6}]"},
+		{"ghép_cặp([1, 2, 3, 4, 5], [6, 7, 8])", "[{1, 6}, {2, 7}, {3, 8}]"},
+		{"ghép_cặp([], [1..3])", "[]"},
+		{"ghép_cặp([\"x\", \"y\", \"z\"], [1..])", "[{\"x\", 1}, {\"y\", 2}, {\"z\", 3}]"},
+		{"ghép_cặp({ n^2 | n thuộc [1..] }, [5, 6, 7])", "[{1, 5}, {4, 6}, {9, 7}]"},
+		{"#ghép_cặp([1..100], [1..])", "100"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "ghép_cặp([1..], [1..])", "Không thể ghép cặp hai tập vô hạn")
+	testError(t, "ghép_cặp([1, 2, 3], 5)", "Không thể dùng 'Số Nguyên' làm tham số")
+
+	// a filter that never matches can't tell the infinite set is empty
+	testIterationError(t, "ghép_cặp({ n^2 | n thuộc [1..], n < 0 }, [1, 2, 3])",
+		"Vòng lặp chạy quá 100 lần, có thể nó không bao giờ dừng")
+}
+
+func TestBitwise(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"12 & 10", "8"},
+		{"12 | 10", "14"},
+		{"12 xor 10", "6"},
+		{"-1 & 0xff", "255"},
+		{"1 << 10", "1024"},
+		{"1 << 70", "1180591620717411303424"},
+		{"(1 << 1000000) >> 999999", "2"},
+		{"5 >> 10^10", "0"},
+		{"1024 >> 3", "128"},
+		{"-8 >> 1", "-4"},
+		{"1 | 2 & 3", "3"},
+		{"1 << 2 + 1", "8"},
+		{"6 & 3 == 2", "đúng"},
+		{"cho A = {x | x thuộc [0..5], x | 1 == 3}; A[1]", "3"},
+		{"cho A = {(x | 1) | x thuộc [0..3]}; A[2]", "3"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"1.5 & 1", "Không thể dùng phép toán bit '&' cho 'Số Thực' và 'Số Nguyên'"},
+		{"1 | 2.0", "Không thể dùng phép toán bit '|' cho 'Số Nguyên' và 'Số Thực'"},
+		{"1 << -1", "Số bit dịch không được là số âm"},
+		{"1 << 10^10", "Số bit dịch trái không được lớn hơn 1000000"},
+		{"1 << 1000001", "Số bit dịch trái không được lớn hơn 1000000"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestBaseBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"nhị_phân(10)", `"1010"`},
+		{"nhị_phân(0)", `"0"`},
+		{"nhị_phân(-5)", `"-101"`},
+		{"nhị_phân(0b1101)", `"1101"`},
+		{"thập_lục(255)", `"FF"`},
+		{"thập_lục(-4096)", `"-1000"`},
+		{"thập_lục(2^64)", `"10000000000000000"`},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "nhị_phân(1.5)", "Không thể dùng 'Số Thực' làm tham số")
+}
+
+func TestRepeatingDecimalHint(t *testing.T) {
+	tests := []struct {
+		input    string
+		teaching bool
+		warnings int
+	}{
+		{"1/3", true, 1},
+		{"1/3", false, 0},
+		{"1/4", true, 0},
+		{"3/6", true, 0},
+		{"1/3 + 2/7", true, 1},
+		{"1.0/3", true, 0},
+	}
+
+	for _, test := range tests {
+		config := NewConfig()
+		config.Teaching = test.teaching
+
+		_, errors := EvalFromInput(test.input, "", object.NewEnvironment(), config)
+		if len(errors.Warnings) != test.warnings {
+			t.Errorf("input %q has wrong number of warnings. want=%d, got=%d",
+				test.input, test.warnings, len(errors.Warnings))
+		}
+	}
+
+	config := NewConfig()
+	config.Teaching = true
+	_, errors := EvalFromInput("1/3", "", object.NewEnvironment(), config)
+	expected := "1/3 là số thập phân vô hạn tuần hoàn nên được giữ ở dạng phân số để tính chính xác, dùng số thực(1/3) nếu cần giá trị thập phân"
+	if errors.Warnings[0].Message != expected {
+		t.Errorf("wrong warning. want=%q, got=%q", expected, errors.Warnings[0].Message)
+	}
+
+	_, errors = EvalFromInput("2/9", "", object.NewEnvironment(), config)
+	if len(errors.Warnings) != 0 {
+		t.Errorf("hint should be given once per session, got %d warnings", len(errors.Warnings))
+	}
+}
+
+func TestEnglishErrors(t *testing.T) {
+	t.Setenv(errorhandler.LOCALE_ENV, "en")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x + 1", "'x' is not defined"},
+		{"1 / 0", "Cannot divide by 0"},
+		{"cho a = 1\na + \"b\"", "Cannot add 'Integer' and 'String'"},
+		{"[1, 2, 3][5]", "Index 5 is out of the length 3 of 'Array'"},
+		// names from the program are never translated
+		{"xuất Lỗi trình thông dịch", "'Lỗi trình thông dịch' is not defined"},
+		{"cho Chuỗi = 1\nChuỗi + \"b\"", "Cannot add 'Integer' and 'String'"},
+		{"cho a = 1 )", "Invalid syntax"},
+		{"cho a = \"b", "Missing the closing \" of a string"},
+	}
+
+	for _, test := range tests {
+		_, errors := EvalFromInput(test.input, "", object.NewEnvironment(), NewConfig())
+		if got := errors.FirstMessage(); got != test.expected {
+			t.Errorf("input %q has wrong error. want=%q, got=%q", test.input, test.expected, got)
+		}
+	}
+}
+
+func TestReturnStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hàm f(x):\n    trả về x + 1\n    x + 100\nf(1)", "2"},
+		{"hàm f():\n    trả về\n    1\nf()", "rỗng"},
+		{"hàm f(x):\n    => x\nf(5)", "5"},
+		{"hàm tìm(A):\n    với mỗi x thuộc A:\n        với mỗi y thuộc A:\n            nếu x + y == 7:\n                trả về {x, y}\n    trả về 0\ntìm([1..6])", "{1, 6}"},
+		{"hàm f(n):\n    cho i = 0\n    với i < n:\n        nếu i == 3:\n            trả về i\n        i = i + 1\n    -1\nf(10)", "3"},
+		{"cho đếm = 0\nhàm f():\n    trả về 1\n    đếm = đếm + 1\nf()\nf()\nđếm", "0"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	// statements after 'trả về' don't run
+	var stdout bytes.Buffer
+	config := NewConfig()
+	config.Stdout = &stdout
+	EvalFromInput("hàm f():\n    xuất 1\n    trả về 2\n    xuất 3\nxuất f()", "", object.NewEnvironment(), config)
+	if stdout.String() != "1 \n2 \n" {
+		t.Errorf("statements after 'trả về' should be skipped, got output %q", stdout.String())
+	}
+
+	testError(t, "trả về 1", "'trả về' chỉ dùng được bên trong hàm")
+	testError(t, "=> 1", "'=>' chỉ dùng được bên trong hàm")
+	testError(t, "với mỗi x thuộc [1..3]:\n    trả về x", "'trả về' chỉ dùng được bên trong hàm")
+	testError(t, "cho x = 1\nnếu x > 0:\n    trả về\nx", "'trả về' chỉ dùng được bên trong hàm")
+}
+
+func TestWhileLoop(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho i = 0\ntrong khi i < 10:\n    i = i + 1\ni", "10"},
+		{"cho n = 1; cho k = 0\ntrong khi n < 1000:\n    n = 2n\n    k = k + 1\nk", "10"},
+		{"cho i = 0\ntrong khi sai:\n    i = 1\ni", "0"},
+		{"cho s = 0; cho i = 1\ntrong khi i <= 3:\n    cho bình phương = i^2\n    s = s + bình phương\n    i = i + 1\ns", "14"},
+		{"cho n = 5\nkhi n > 0:\n    n = n - 1\nn", "0"},
+		{"cho n = 5; cho s = 0\nkhi n > 0:\n    s = s + n\n    n = n - 1\ns", "15"},
+		{"cho n = 10\nkhi đúng:\n    n = n - 1\n    nếu n == 3: dừng\nn", "3"},
+		{"cho n = 3\nkhi n > 0:\n    cho bước = n\n    n = n - bước\nn", "0"},
+		{"cho i = 0; cho s = 0\nkhi i < 5:\n    i = i + 1\n    nếu i % 2 == 0: tiếp\n    s = s + i\ns", "9"},
+		{"cho s = 0\nngoài: khi đúng:\n    với mỗi x thuộc [1..3]:\n        s = s + x\n        nếu x == 2: dừng ngoài\ns", "3"},
+		{"hàm đếm(n):\n    khi đúng:\n        nếu n == 0: trả về \"xong\"\n        n = n - 1\nđếm(4)", "\"xong\""},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testIterationError(t, "trong khi đúng:\n    1", "Vòng lặp chạy quá 100 lần, có thể nó không bao giờ dừng")
+}
+
+func TestCharBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"ký_tự(65)", `"A"`},
+		{"ký_tự(0x1EC7)", `"ệ"`},
+		{"ký_tự(273)", `"đ"`},
+		{`mã("A")`, "65"},
+		{`mã("abc")`, "97"},
+		{`mã("ệ")`, "7879"},
+		{`mã("Đà Nẵng")`, "272"},
+		{`ký_tự(mã("ơ"))`, `"ơ"`},
+
+		// 'mã' is a common name, declaring it hides the builtin
+		{"cho mã = 5\nmã + 1", "6"},
+		{"cho mã = 5\nmã = 7\nmã", "7"},
+		{`cho mã = 5` + "\n" + `"A".mã()`, "65"},
+		{"hàm f():\n    cho mã = 1\n    trả về mã\nf() + mã(\"B\")", "67"},
+		{"cho mã(x) = x * 2\nmã(4)", "8"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"ký_tự(-1)", "Mã Unicode '-1' không hợp lệ"},
+		{"ký_tự(0xD800)", "Mã Unicode '55296' không hợp lệ"},
+		{"ký_tự(0x110000)", "Mã Unicode '1114112' không hợp lệ"},
+		{"ký_tự(2^32 + 65)", "Mã Unicode '4294967361' không hợp lệ"},
+		{`mã("")`, "Chuỗi rỗng không có ký tự nào"},
+		{"mã(65)", "Không thể dùng 'Số Nguyên' làm tham số"},
+		{"mã = 5", "Không thể gán giá trị cho 'mã'"},
+		{"cho mã = 5\ncho mã = 6", "'mã' đã được khởi tạo"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestScientificNotation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1.5e2", "150"},
+		{"1e3 + 1", "1001"},
+		{"2.5e-1 * 4", "1"},
+		{"1_000e-3", "1"},
+		{"cho e = 3; 2e", "6"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestCopyBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"sao chép([[1, 2, 3], [4], [5]])", "[[1, 2, 3], [4], [5]]"},
+		{"sao chép({1, {2, 3}})", "{1, {2, 3}}"},
+		{"sao chép(5)", "5"},
+		{`sao chép("chữ")`, `"chữ"`},
+		{"sao_chép([1, 2, 3])", "[1, 2, 3]"},
+		// changing the copy leaves the source as it is, at every level
+		{"cho a = [[1, 2, 3], [4], [5]]\ncho b = sao_chép(a)\nb[0][1] = 9\nb[2] = 0\na", "[[1, 2, 3], [4], [5]]"},
+		{"cho a = [[1, 2, 3], [4], [5]]\ncho b = sao_chép(a)\nb[0][1] = 9\nb", "[[1, 9, 3], [4], [5]]"},
+		{"cho a = {1, [2, 3, 4]}\ncho b = a.sao_chép()\nb[1][0] = 0\na", "{1, [2, 3, 4]}"},
+		{"cho a = [[1, 2, 3], [4], [5]]\ncho b = a\nb[0][1] = 9\na", "[[1, 9, 3], [4], [5]]"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	source := testEval(t, "cho a = [[1, 2, 3], [4], [5]]; a").(*object.Array)
+	cp := testEval(t, "cho a = [[1, 2, 3], [4], [5]]; sao chép(a)").(*object.Array)
+	if cp.Data[0] == source.Data[0] {
+		t.Errorf("nested array is not copied")
+	}
+}
+
+func TestExactDivision(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected object.Object
+	}{
+		{"6 / 3", object.NewInt(big.NewInt(2))},
+		{"-8 / 4", object.NewInt(big.NewInt(-2))},
+		{"1/3 * 3", object.NewInt(big.NewInt(1))},
+		{"1/3 + 2/3", object.NewInt(big.NewInt(1))},
+		{"5/2 - 1/2", object.NewInt(big.NewInt(2))},
+		{"(3/4) / (3/8)", object.NewInt(big.NewInt(2))},
+		{"(2/3)^-1 * 2/3", object.NewInt(big.NewInt(1))},
+		{"4 / 6", object.NewQuotient(big.NewInt(2), big.NewInt(3))},
+		{"-2 / 4", object.NewQuotient(big.NewInt(-1), big.NewInt(2))},
+		{"1/3 + 1/6", object.NewQuotient(big.NewInt(1), big.NewInt(2))},
+	}
+
+	for _, test := range tests {
+		value := testEval(t, test.input)
+		if value.Type() != test.expected.Type() || value.Display() != test.expected.Display() {
+			t.Errorf("input %q wrong result. want=%s (%s), got=%s (%s)", test.input,
+				test.expected.Display(), test.expected.Type(), value.Display(), value.Type())
+		}
+	}
+
+	// mixing with reals gives reals
+	if value := testEval(t, "1/4 + 0.5"); value.Type() != object.RealObj {
+		t.Errorf("quotient plus real should be '%s', got '%s'", object.RealObj, value.Type())
+	}
+	testDisplay(t, "(6/3)!", "2")
+	testDisplay(t, "cho xs = [1, 2, 3]\nxs[4/2]", "3")
+}
+
+func TestRationalBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hữu tỉ(0.25)", "1/4"},
+		{"hữu tỉ(0.25) == 1/4", "đúng"},
+		{"hữu tỉ(1 / 3.0)", "1/3"},
+		{"hữu tỉ(0.1 + 0.2)", "3/10"},
+		{"hữu tỉ(-2.5)", "-5/2"},
+		{"hữu tỉ(3.0)", "3"},
+		{"hữu tỉ(2/6)", "1/3"},
+		{"số thực(1/4)", "0.25"},
+		{"số thực(1/3)", "0.3333333333333333"},
+		{"số thực(2)", "2"},
+		{"hữu tỉ(số thực(5/7))", "5/7"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "hữu tỉ(căn(2))", "'1.4142135623730950488' không phải số hữu tỉ có mẫu số không quá 10000")
+	testError(t, "hữu tỉ(0.3333333)", "'0.3333333' không phải số hữu tỉ có mẫu số không quá 10000")
+	testError(t, "hữu tỉ(ln(0))", "'-Inf' không phải số hữu tỉ có mẫu số không quá 10000")
+	testError(t, "hữu tỉ(-ln(0))", "'+Inf' không phải số hữu tỉ có mẫu số không quá 10000")
+	testError(t, "số thực(\"a\")", "Không thể dùng 'Chuỗi' làm tham số")
+}
+
+func TestZeroDivision(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 / 0", "Không thể chia cho 0"},
+		{"-5 / (3 - 3)", "Không thể chia cho 0"},
+		{"1.5 / 0", "Không thể chia cho 0"},
+		{"1 / 0.0", "Không thể chia cho 0"},
+		{"0.0 / 0.0", "Không thể chia cho 0"},
+		{"(1/2) / 0", "Không thể chia cho 0"},
+		{"(1/2) / (1/3 - 1/3)", "Không thể chia cho 0"},
+		{"2 / (1/2 - 1/2)", "Không thể chia cho 0"},
+		{"(1/2) / 0.0", "Không thể chia cho 0"},
+		{"(1 + 2I) / 0", "Không thể chia cho 0"},
+		{"(1 + 2I) / 0.0", "Không thể chia cho 0"},
+		{"1 / (0 * I)", "Không thể chia cho 0"},
+		{"cho f(x) = 1/x\nf(0)", "Không thể chia cho 0"},
+		{"[1..5, 0]", "Bước nhảy của khoảng phải khác 0"},
+		{"[1..5, 0.0]", "Bước nhảy của khoảng phải khác 0"},
+		{"[1.., 1 - 1]", "Bước nhảy của khoảng phải khác 0"},
+		{`[1..5, "a"]`, "Không thể dùng 'Chuỗi' làm bước nhảy"},
+	}
+
+	for _, test := range tests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestModulo(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"7 % 3", "1"},
+		{"-7 % 3", "2"},
+		{"7 % -3", "1"},
+		{"-7 % -3", "2"},
+		{"6 % 3", "0"},
+		{"5.5 % 2", "1.5"},
+		{"-5.5 % 2", "0.5"},
+		{"5.5 % -2", "1.5"},
+		{"7 % 2.5", "2"},
+		{"7/2 % 1", "1/2"},
+		{"-1/2 % 1", "1/2"},
+		{"5 % (3/2)", "1/2"},
+		{"2 + 7 % 3 * 2", "4"},
+		{"7 mod 3", "1"},
+		{"-7 mod 3", "2"},
+		{"7 mod -3", "1"},
+		{"-7 mod -3", "2"},
+		{"2 + 7 mod 3 * 2", "4"},
+		{"cho a = 17\na mod 5", "2"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"7 % 0", "Không thể chia cho 0"},
+		{"7.5 % 0.0", "Không thể chia cho 0"},
+		{"7/2 % 0", "Không thể chia cho 0"},
+		{"7 mod 0", "Không thể chia cho 0"},
+		{"7 / 0", "Không thể chia cho 0"},
+		{"1 / (1/2 - 1/2)", "Không thể chia cho 0"},
+		{`"a" % 2`, "Không thể chia lấy dư 'Chuỗi' với 'Số Nguyên'"},
+		{"ln(0) % 2", "Không thể chia lấy dư 'Số Thực' với 'Số Nguyên'"},
+		{"2 mod -ln(0)", "Không thể chia lấy dư 'Số Nguyên' với 'Số Thực'"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestRepeatLoop(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho s = 0\nlặp 5 lần:\n    s = s + 2\ns", "10"},
+		{"cho s = 0\nlặp 0 lần:\n    s = s + 2\ns", "0"},
+		{"cho n = 3; cho s = 1\nlặp n + 1 lần:\n    s = 2s\ns", "16"},
+		{"cho s = 0\nlặp 2 lần:\n    lặp 3 lần:\n        s = s + 1\ns", "6"},
+		{"hàm f():\n    lặp 10 lần:\n        trả về 1\n    2\nf()", "1"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"lặp -1 lần:\n    1", "Số lần lặp phải là số nguyên không âm thay vì '-1'"},
+		{"lặp 2.5 lần:\n    1", "Số lần lặp phải là số nguyên không âm thay vì '2.5'"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestBreakContinue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho s = 0\nvới mỗi x thuộc [1..10]:\n    nếu x > 3:\n        dừng\n    s = s + x\ns", "6"},
+		{"cho s = 0\nvới mỗi x thuộc [1..10]:\n    nếu x % 2 == 0:\n        tiếp\n    s = s + x\ns", "25"},
+		{"cho s = 0\nvới mỗi x thuộc [1..]:\n    nếu x > 100:\n        dừng\n    s = s + 1\ns", "100"},
+		{"cho s = 0\nvới mỗi x thuộc [1..3], y thuộc [1..3]:\n    nếu y == 2:\n        dừng\n    s = s + 1\ns", "1"},
+		{"cho s = 0\nvới mỗi x thuộc [1..3]:\n    với mỗi y thuộc [1..3]:\n        nếu y == 2:\n            dừng\n        s = s + 1\ns", "3"},
+		{"cho s = 0\nvới mỗi x thuộc [1..3]:\n    với mỗi y thuộc [1..3]:\n        nếu y == 2:\n            tiếp\n        s = s + 1\ns", "6"},
+		{"cho i = 0\ntrong khi đúng:\n    i = i + 1\n    nếu i == 5:\n        dừng\ni", "5"},
+		{"cho i = 0; cho s = 0\ntrong khi i < 5:\n    i = i + 1\n    nếu i == 3:\n        tiếp\n    s = s + i\ns", "12"},
+		{"cho s = 0\nlặp 10 lần:\n    s = s + 1\n    nếu s == 4:\n        dừng\ns", "4"},
+		{"cho s = 0\nlặp 3 lần:\n    tiếp\n    s = s + 1\ns", "0"},
+		{"hàm f():\n    với mỗi x thuộc [1..]:\n        nếu x == 7:\n            dừng\n    trả về 1\nf()", "1"},
+		{"cho s = 0\nlặp i từ 1 đến 10:\n    nếu i % 3 != 0:\n        tiếp\n    s = s + i\ns", "18"},
+		{"cho n = 0\nkhi đúng:\n    n = n + 1\n    nếu n >= 4: dừng\nn", "4"},
+		{"cho xs = [0, 0, 0, 0]\nvới mỗi i thuộc [0..3]:\n    xs[i] = 1\n    nếu i == 1: dừng\nxs", "[1, 1, 0, 0]"},
+		{"đúng", "đúng"},
+		{"dung", "đúng"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"dừng", "'dừng' chỉ dùng được bên trong vòng lặp"},
+		{"nếu đúng:\n    tiếp", "'tiếp' chỉ dùng được bên trong vòng lặp"},
+		{"hàm f():\n    dừng\nvới mỗi x thuộc [1..3]:\n    f()", "'dừng' chỉ dùng được bên trong vòng lặp"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestLabeledLoop(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho s = 0\nvòng ngoài: với mỗi x thuộc [1..3]:\n    với mỗi y thuộc [1..3]:\n        nếu y == 2:\n            thoát vòng ngoài\n        s = s + 1\ns", "1"},
+		{"cho s = 0\nvòng ngoài: với mỗi x thuộc [1..3]:\n    với mỗi y thuộc [1..3]:\n        nếu y == 2:\n            tiếp vòng ngoài\n        s = s + 1\ns", "3"},
+		{"cho s = 0\nngoài: với mỗi x thuộc [1..3]:\n    trong: với mỗi y thuộc [1..3]:\n        nếu y == 2: dừng trong\n        s = s + 1\ns", "3"},
+		{"cho s = 0\nngoài: với mỗi x thuộc [1..], y thuộc [1..3]:\n    nếu x == 2: dừng ngoài\n    s = s + 1\ns", "3"},
+		{"cho s = 0\nngoài: lặp 5 lần:\n    lặp i từ 1 đến 5:\n        khi đúng:\n            s = s + 1\n            dừng ngoài\ns", "1"},
+		{"cho i = 0; cho s = 0\nngoài: khi i < 3:\n    i = i + 1\n    lặp 3 lần:\n        tiếp ngoài\n    s = s + 1\ns", "0"},
+		{"hàm f():\n    ngoài: với mỗi x thuộc [1..]:\n        với mỗi y thuộc [1..]:\n            nếu x * y == 6: trả về 10x + y\nf()", "16"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"với mỗi x thuộc [1..3]:\n    dừng ngoài", "Không có vòng lặp nào tên 'ngoài' bao quanh 'dừng ngoài'"},
+		{"ngoài: với mỗi x thuộc [1..3]:\n    với mỗi y thuộc [1..3]:\n        tiếp trong", "Không có vòng lặp nào tên 'trong' bao quanh 'tiếp trong'"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestInlineBlock(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"nếu đúng: 1 ngược lại: 2", "1"},
+		{"nếu sai: 1 ngược lại: 2", "2"},
+		{"nếu sai: 1 còn nếu đúng: 2 còn không: 3", "2"},
+		{"nếu sai: 1\ncòn không: 2", "2"},
+		{"nếu sai:\n    1\nngược lại:\n    2", "2"},
+		{"nếu sai: 1\nngược lại:\n    2", "2"},
+		{"cho s = 0\nvới mỗi x thuộc [1..4]: s = s + x\ns", "10"},
+		{"cho i = 0\ntrong khi i < 5: i = i + 1\ni", "5"},
+		{"cho s = 0\nlặp 3 lần: s = s + 2\ns", "6"},
+		{"cho s = 0\nvới mỗi x thuộc [1..10]:\n    nếu x > 3: dừng\n    s = s + x\ns", "6"},
+		{"hàm f(x): x + 1\nf(2)", "3"},
+		{"hàm f(x):\n    nếu x < 0: trả về -x\n    x\nf(-3)", "3"},
+		{"cho s = 0\nvới mỗi x thuộc [1..3]: nếu x != 2: s = s + x\ns", "4"},
+		{"cho s = 0 // tổng\nvới mỗi x thuộc [1..3]: // chạy\n    s = s + x // cộng\n    s = s * 2\ns", "22"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestMatchStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"khớp 0:\n    0: \"không\"\n    1: \"một\"\n    _: \"khác\"", "\"không\""},
+		{"khớp 1:\n    0: \"không\"\n    1: \"một\"\n    _: \"khác\"", "\"một\""},
+		{"khớp 7:\n    0: \"không\"\n    1: \"một\"\n    _: \"khác\"", "\"khác\""},
+		{"khớp 2:\n    0: \"không\"\n    1: \"một\"", "rỗng"},
+		{"khớp 2.0:\n    2: \"hai\"\n    _: \"khác\"", "\"hai\""},
+		{"cho x = 3\nkhớp x + 1:\n    x: \"ba\"\n    4: \"bốn\"", "\"bốn\""},
+		{"khớp 1:\n    _: \"trước\"\n    1: \"sau\"", "\"trước\""},
+		{"khớp 1:\n    1:\n        cho y = 5\n        y * 2\n    _: 0", "10"},
+		{"khớp 5: 0: \"a\"; _: \"b\"", "\"b\""},
+		{"khớp 5: 0: \"a\"; _: \"b\"\n\"sau\"", "\"sau\""},
+		{"khớp \"a\":\n    1: \"số\"\n    \"a\": \"chữ\"", "\"chữ\""},
+		{"khớp [1, 2, 3]:\n    1: \"số\"\n    _: \"khác\"", "\"khác\""},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestMatchInterval(t *testing.T) {
+	branches := "\n    0: \"không\"\n    [0, 10]: \"nhỏ\"\n    (10, 20): \"vừa\"\n    [0..100, 10]: \"chục\"\n    _: \"khác\""
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"khớp 0:" + branches, "\"không\""},
+		{"khớp 5:" + branches, "\"nhỏ\""},
+		{"khớp 10:" + branches, "\"nhỏ\""},
+		{"khớp 10.5:" + branches, "\"vừa\""},
+		{"khớp 20:" + branches, "\"chục\""},
+		{"khớp 25:" + branches, "\"khác\""},
+		{"khớp -1:" + branches, "\"khác\""},
+		{"khớp \"a\":" + branches, "\"khác\""},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestCounterLoop(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho s = 0\nlặp i từ 1 đến 5:\n    s = s + i\ns", "15"},
+		{"cho s = 0\nlặp i từ 1 đến 10 bước 3:\n    s = s + i\ns", "22"},
+		{"cho s = 0\nlặp i từ 5 đến 1:\n    s = 10s + i\ns", "54321"},
+		{"cho s = 0\nlặp i từ 10 đến 1 bước -4:\n    s = s + i\ns", "18"},
+		{"cho s = 0\nlặp i từ 1 đến 5 bước -1:\n    s = s + i\ns", "0"},
+		{"cho s = 0\nlặp i từ 3 đến 3:\n    s = s + i\ns", "3"},
+		{"cho s = 0\nlặp x từ 0 đến 1 bước 0.25:\n    s = s + x\ns", "2.5"},
+		{"cho s = 0\nlặp i từ 1 đến 10: nếu i > 3: dừng ngược lại: s = s + i\ns", "6"},
+		{"cho i = 100\nlặp i từ 1 đến 2: 0\ni", "100"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"lặp i từ 1 đến 5 bước 0: 1", "Bước nhảy của vòng lặp phải khác 0"},
+		{`lặp i từ "a" đến 5: 1`, "Giá trị bắt đầu của vòng lặp phải là một số"},
+		{"lặp i từ 1 đến {1}: 1", "Giá trị kết thúc của vòng lặp phải là một số"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775808 - 1", "-9223372036854775809"},
+		{"4294967296 * 4294967296", "18446744073709551616"},
+		{"123456789012345678901234567890", "123456789012345678901234567890"},
+		{"123456789012345678901234567890 - 123456789012345678901234567889", "1"},
+		{"2^64", "18446744073709551616"},
+		{"(-3)^41", "-36472996377170786403"},
+		{"2^100 / 2^99", "2"},
+		{"2^-1", "1/2"},
+		{"2^-64", "1/18446744073709551616"},
+		{"(-2)^-3", "-1/8"},
+		{"(2/3)^-2", "9/4"},
+		{"(1/2)^-1", "2"},
+		{"2^0", "1"},
+		{"2^100", "1267650600228229401496703205376"},
+		{"30!", "265252859812191058636308480000000"},
+		{"123456789012345678901234567890 * 987654321098765432109876543210",
+			"121932631137021795226185032733622923332237463801111263526900"},
+		{"-(2^70)", "-1180591620717411303424"},
+		{"2^64 - 2^64 + 1", "1"},
+		{"10^30 / 10^28", "100"},
+		{"(2^64) là Số Nguyên", "đúng"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "0^-1", "Không thể chia cho 0")
+	testError(t, "(0/5)^-2", "Không thể chia cho 0")
+}
+
+func TestSummation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"tổng(i, 1, 10, i)", "55"},
+		{"tổng(i, 1, 0, i)", "0"},
+		{"cho n = 100\ntổng(i, 1, n, i^2)", "338350"},
+		{"tổng(k, 1, 3, 1/k)", "11/6"},
+		{"tổng(k, 1, 4, 0.5)", "2"},
+		{"tổng(i, 1, 3, tổng(j, 1, i, j))", "10"},
+		{"tổng(i, 0, 64, 2^i)", "36893488147419103231"},
+		{"cho i = 7\ntổng(i, 1, 3, i)\ni", "7"},
+		{"hàm tổng(a, b): a + b\ntổng(1, 2)", "3"},
+		{"tổng(x thuộc [1..100], x)", "5050"},
+		{"tổng(x thuộc [1,100], x)", "5050"},
+		{"tổng(x thuộc [0, 10), x)", "45"},
+		{"tổng(x thuộc {1, 2, 3}, x^2)", "14"},
+		{"tổng(x thuộc {1} giao {2}, x)", "0"},
+		{"tổng(x thuộc [1..10], x % 2 == 0, x)", "30"},
+		{"tổng(x thuộc [1..3], y thuộc [1..3], x * y)", "36"},
+		{"tổng(x thuộc [1..3], 1/x)", "11/6"},
+		{"tổng(x thuộc [1..4], x / 2.0)", "5"},
+		{"cho A = [2, 4, 8]\ntổng(x thuộc A, 1/x)", "7/8"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "tổng(i, 1, 3, \"a\")", "Biểu thức trong 'tổng' phải là một số thay vì 'Chuỗi'")
+	testError(t, "tổng(x thuộc [1..3], \"a\")", "Biểu thức trong 'tổng' phải là một số thay vì 'Chuỗi'")
+	testError(t, "tổng(x thuộc [0.5, 100], x)", "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được")
+	testError(t, "tổng(1, 3, i)", "Cần 4 tham số thay vì 3, ví dụ tổng(i, 1, n, i^2)")
+	testError(t, "tổng(2, 1, 3, 1)", "Tham số đầu tiên của 'tổng' phải là tên của biến chạy")
+
+	// too many terms are refused instead of running for hours
+	testError(t, "tổng(i, 1, 10^12, i)", "'tổng' có hơn 10000000 số hạng, quá nhiều để tính")
+	testError(t, "tổng(x thuộc [1..], x)", "'tổng' trên một tập vô hạn không bao giờ tính xong")
+	testError(t, "tổng(x thuộc [1..], x < 5, x)", "'tổng' trên một tập vô hạn không bao giờ tính xong")
+	testIterationError(t, "tổng(i, 1, 101, i)", "'tổng' có hơn 100 số hạng, quá nhiều để tính")
+	testIterationError(t, "tổng(x thuộc [1..1000], x)", "'tổng' có hơn 100 số hạng, quá nhiều để tính")
+	testIterationError(t, "tổng(x thuộc [1..20], y thuộc [1..20], x * y)", "'tổng' có hơn 100 số hạng, quá nhiều để tính")
+}
+
+func TestProduct(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"tích(i, 1, 5, i)", "120"},
+		{"tích(i, 1, 0, i)", "1"},
+		{"tích(i, 1, 30, i) == 30!", "đúng"},
+		{"tích(i, 1, 40, 2)", "1099511627776"},
+		{"tích(k, 2, 4, 1 - 1/k)", "1/4"},
+		{"tích(i, 1, 3, tổng(j, 1, i, j))", "18"},
+		{"tích(x thuộc [1..5], x)", "120"},
+		{"tích(x thuộc [1,5], x)", "120"},
+		{"tích(x thuộc (1, 5], x)", "120"},
+		{"cho n = 6\ntích(x thuộc [1, n], x)", "720"},
+		{"tích(x thuộc [3, 1], x)", "1"},
+		{"tích(x thuộc {1} giao {2}, x)", "1"},
+		{"cho n = 25\ntích(x thuộc [1..n], x) == n!", "đúng"},
+		{"tích(x thuộc [1..30], x)", "265252859812191058636308480000000"},
+		{"tích(x thuộc [2..4], 1 - 1/x)", "1/4"},
+		{"tích(x thuộc [1..3], x / 2.0)", "0.75"},
+		{"tích(x thuộc [1..10], x % 2 == 1, x)", "945"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "tích(i, 1, 3, [i])", "Biểu thức trong 'tích' phải là một số thay vì 'Mảng'")
+	testError(t, "tích(i, \"a\", 3, i)", "Cận dưới của 'tích' phải là một số")
+	testError(t, "tích(x thuộc [1, 5.5], x)", "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được")
+	testError(t, "tích(x thuộc [1..3], [x])", "Biểu thức trong 'tích' phải là một số thay vì 'Mảng'")
+	testError(t, "tích(i, 1, 10^12, i)", "'tích' có hơn 10000000 số hạng, quá nhiều để tính")
+	testError(t, "tích(x thuộc [1..], x)", "'tích' trên một tập vô hạn không bao giờ tính xong")
+}
+
+func TestFactorial(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0!", "1"},
+		{"1!", "1"},
+		{"5!", "120"},
+		{"25!", "15511210043330985984000000"},
+		{"-3!", "-6"},
+		{"2^3!", "64"},
+		{"3!^2", "36"},
+		{"2 * 3! + 1", "13"},
+		{"(1 + 2)!", "6"},
+		{"3!!", "720"},
+		{"5 != 3", "đúng"},
+		{"!sai", "đúng"},
+		{"!(3! == 6)", "sai"},
+		{"cho n = 4\nn! == 24", "đúng"},
+		{"cho n = 4\nn! != n", "đúng"},
+		{"tổng(i, 1, 4, i!)", "33"},
+		{"1/2!", "1/2"},
+		{"100000! % 1000003", "353746"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"(-1)!", "Chỉ tính được giai thừa của số nguyên không âm thay vì '-1'"},
+		{"2.5!", "Chỉ tính được giai thừa của số nguyên không âm thay vì '2.5'"},
+		{"(1/2)!", "Chỉ tính được giai thừa của số nguyên không âm thay vì '1/2'"},
+		{`"a"!`, "Chỉ tính được giai thừa của số nguyên không âm thay vì '\"a\"'"},
+		{"(10^7)!", "'10000000' quá lớn để tính giai thừa, chỉ tính được đến 100000!"},
+		{"100001!", "'100001' quá lớn để tính giai thừa, chỉ tính được đến 100000!"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestSetComprehension(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{ x : x thuộc [1..10], x^2 > 20 }", "{5, 6, 7, 8, 9, 10}"},
+		{"{ x : x thuộc [1..10] và x^2 > 20 và x % 2 == 0 }", "{6, 8, 10}"},
+		{"{ x % 3 : x thuộc [1..10] }", "{0, 1, 2}"},
+		{"{ x : x thuộc {1, 2, 2, 1, 3} }", "{1, 2, 3}"},
+		{"{ x : x thuộc [1..3], x > 5 }", "{}"},
+		{"{ x + y : x thuộc [1..3], y thuộc [1..3] }", "{2, 3, 4, 5, 6}"},
+		{"cho A = { x : x thuộc [1..5] }\n#A", "5"},
+		{"{ x : x thuộc {0.5, 1, 1.5, 2}, x thuộc [0, 1] }", "{0.5, 1}"},
+		{"{ x : x thuộc [-3..3], x thuộc (0, 2] }", "{1, 2}"},
+		{"{ x / 4 : x thuộc [0..8], x / 4 thuộc [0.5, 1) }", "{1/2, 3/4}"},
+		{"cho s = 0\nvới mỗi x thuộc {0.2, 0.7, 1.2}, x thuộc [0, 1): s = s + x\ns", "0.9"},
+		// an interval with integer bounds gives its integers to a new variable
+		{"{ x : x thuộc [1, 10], x > 7 }", "{8, 9, 10}"},
+		{"{ x : x thuộc (0, 3) }", "{1, 2}"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"{ x : x thuộc [0.5, 10], x > 2 }", "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được"},
+		{"{ x : x thuộc 5 }", "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được"},
+
+		// only a name bound earlier in the same header is filtered, an outer
+		// one doesn't make the interval a constraint
+		{"cho t = 2\n{ x : x thuộc [1..3], t thuộc [0.5, 1.5] }", "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được"},
+		{"cho x = 1\nvới mỗi x thuộc [0.5, 1.5]: xuất x", "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được"},
+		{"cho x = 1\ntổng(x thuộc [0.5, 1.5], x)", "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được"},
+		{"cho x = 1\n{ x : x thuộc [0.5, 1.5] }", "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestQuantifier(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"với mọi x thuộc [1..5], x > 0", "đúng"},
+		{"với mọi x thuộc [1..5], x > 1", "sai"},
+		{"với mọi x thuộc [1,5], x > 0", "đúng"},
+		{"tồn tại x thuộc [1, 5]: x^2 == 16", "đúng"},
+		{"tồn tại x thuộc {1, 2, 3}: x^2 == 4", "đúng"},
+		{"tồn tại x thuộc {1, 2, 3}: x^2 == 5", "sai"},
+		{"với mọi x thuộc [1..3], y thuộc [1..3], x * y <= 9", "đúng"},
+		{"với mọi x thuộc [1..10], x % 2 == 0, x^2 % 4 == 0", "đúng"},
+		{"cho A = {2, 4, 6}\ntồn tại x thuộc A, x > 3, x % 3 == 0", "đúng"},
+		{"nếu với mọi x thuộc [1..3], x < 4: 1 còn không: 2", "1"},
+		// an empty set has no counterexample and no witness
+		{"với mọi x thuộc {1} giao {2}, sai", "đúng"},
+		{"tồn tại x thuộc {1} giao {2}: đúng", "sai"},
+		{"với mọi x thuộc [1..10], x > 10, sai", "đúng"},
+		// the first witness or counterexample stops the iteration
+		{"tồn tại n thuộc [1..], n^2 > 50", "đúng"},
+		{"với mọi n thuộc [1..], n < 100", "sai"},
+		{"cho đếm = 0\ncho f(x) = (đếm = đếm + 1; x > 2)\ntồn tại x thuộc [1..10]: f(x)\nđếm", "3"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "với mọi x thuộc [1, 5.5], x > 0", "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được")
+	testError(t, "cho x = 5\nvới mọi x thuộc [0.5, 1.5], x > 0", "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được")
+	testError(t, "cho x = 1\ntồn tại x thuộc [0.5, 1.5]: x > 0", "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được")
+	testError(t, "tồn tại x thuộc [1..3]: y", "'y' chưa được khởi tạo")
+}
+
+func TestSetKey(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// a literal is a tuple so its order matters
+		{"{1, 2} == {2, 1}", "sai"},
+		{"{1, 2} == {1, 2}", "đúng"},
+		{"{1, 1} == {1}", "sai"},
+		{"{{1, 2}, 3} == {3, {2, 1}}", "sai"},
+		{"khoảng_cách({0, 3}, {4, 0})", "5"},
+		// sets made by set operations don't
+		{"{ x : x thuộc {2, 1, 1} } == { x : x thuộc {1, 2} }", "đúng"},
+		{"{ x : x thuộc {2, 1, 1} } == {1, 2}", "đúng"},
+		{"{1, 2} hợp {3} == {3, 2} hợp {1}", "đúng"},
+		{"{1, 2} == {1, 3}", "sai"},
+		{"{1, 2} != {1, 2, 3}", "đúng"},
+		{"{ {x, 3}: x với x thuộc [1..2] }", "{{1, 3}: 1, {2, 3}: 2}"},
+		{"cho m = { {x, 3}: x với x thuộc [1..2] }\nm[{1, 3}]", "1"},
+		{"cho m = { { y : y thuộc {x, 3} }: x với x thuộc [1..2] }\nm[{3} hợp {1}]", "1"},
+		{"cho m = { {x, 3}: x với x thuộc [1..2] }\nm[{2, 3}] = 0\nm", "{{1, 3}: 1, {2, 3}: 0}"},
+		{"cho A = {1}\ncho m = { A: 1 với x thuộc [1..1] }\nA[0] = 5\nm[{1}]", "1"},
+		{"{ {x % 2, 2} : x thuộc [1..4] }", "{{1, 2}, {0, 2}}"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "{ {[x]}: 1 với x thuộc [1..2] }", "Không thể dùng 'Tập Hợp' làm khóa của từ điển")
+	testError(t, "cho m = { {x, 3}: x với x thuộc [1..2] }\nm[{3, 1}]", "Khóa {3, 1} không có trong từ điển")
+}
+
+func TestMapComprehension(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{ x: x^2 với x thuộc [1..5] }", "{1: 1, 2: 4, 3: 9, 4: 16, 5: 25}"},
+		{"{ x % 3: x với x thuộc [1..7] }", "{1: 7, 2: 5, 0: 6}"},
+		{"{ x: x với x thuộc [1..5], x > 3 }", "{4: 4, 5: 5}"},
+		{"{ x: 0 với x thuộc [1..3] và x > 5 }", "{}"},
+		{"cho m = { x: x^2 với x thuộc [1..5] }\nm[3]", "9"},
+		{"cho m = { x: x^2 với x thuộc [1..5] }\nm[2.0]", "4"},
+		{"cho m = { x: x với x thuộc [1..2] }\nm[3] = 9\nm[1] = 0\nm", "{1: 0, 2: 2, 3: 9}"},
+		{"{ x > 1: x với x thuộc [1..3] }", "{sai: 1, đúng: 3}"},
+		{"{ x: x với x thuộc [1..2] } là Từ Điển", "đúng"},
+		{"{i: i^2 với i thuộc [1,5]}", "{1: 1, 2: 4, 3: 9, 4: 16, 5: 25}"},
+		{"{ x % 2: x với x thuộc [1..4] }", "{1: 3, 0: 4}"},
+		// a map is the set of its keys for '#', 'thuộc' and loops
+		{"cho m = {i: i^2 với i thuộc [1,5]}\n#m", "5"},
+		{"cho m = {i: i^2 với i thuộc [1,5]}\nsốLượng(m)", "5"},
+		{"cho m = {i: i^2 với i thuộc [1,5]}\n3 thuộc m", "đúng"},
+		{"cho m = {i: i^2 với i thuộc [1,5]}\n9 thuộc m", "sai"},
+		{"cho m = {i: i^2 với i thuộc [1,5]}\ncho s = 0\nvới mỗi k thuộc m: s = s + m[k]\ns", "55"},
+		{"cho m = {i: i^2 với i thuộc [1,5]}\n{ m[k] : k thuộc m, k > 3 }", "{16, 25}"},
+		{"cho m = { x: x với x thuộc [1..2] }\nm[ln(0)] = 3\nm[ln(0)] + m[1]", "4"},
+		{"cho m = { x: x với x thuộc [1..2] }\nln(0) thuộc m", "sai"},
+		{"cho m = { x: x với x thuộc [1..2] }\nm[ln(0)] = 3\nm[-ln(0)] = 4\n#m", "4"},
+		{"cho f(x) = x % 2\ncho g = gom_nhóm(f, [1, 2, 3, 4])\n{ k: #g[k] với k thuộc g }", "{1: 2, 0: 2}"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho m = { x: x với x thuộc [1..2] }\nm[5]", "Khóa 5 không có trong từ điển"},
+		{"{ [1..x]: x với x thuộc [1..2] }", "Không thể dùng 'Tập Hợp' làm khóa của từ điển"},
+		{"cho m = { x: x với x thuộc [1..2] }\nđúng < m", "Không thể so sánh 'Logic' với 'Từ Điển'"},
+		{"cho m = { x: x với x thuộc [1..2] }\n1 < m", "Không thể so sánh 'Số Nguyên' với 'Từ Điển'"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{1, 2, 3} hợp {2, 3, 4}", "{1, 2, 3, 4}"},
+		{"{1, 2, 3} giao {2, 3, 4}", "{2, 3}"},
+		{"{1, 2, 3} trừ {2, 3, 4}", "{1}"},
+		{"{1, 1, 2} hợp {2}", "{1, 2}"},
+		{"[1..3] hợp [7..9]", "{1, 2, 3, 7, 8, 9}"},
+		{"#([1..3] hợp [7..9])", "6"},
+		{"[1..10] giao [5..20, 5]", "{5, 10}"},
+		{"[1..] giao {0, 3, 5}", "{3, 5}"},
+		{"{1, 2} giao {3}", "{}"},
+		{"[1..5] trừ [2..4]", "{1, 5}"},
+		{"{1, 2} hợp {3} giao {3, 4}", "{1, 2, 3}"},
+		{"7 thuộc [1..] hợp {0}", "đúng"},
+		{"7 thuộc [1..] trừ [5..]", "sai"},
+		{"2 thuộc [5..]", "sai"},
+		{"{1, 2, 3} ∪ {3, 4}", "{1, 2, 3, 4}"},
+		{"{1, 2, 3} ∩ {3, 4}", "{3}"},
+		{`{1, 2, 3} \ {3, 4}`, "{1, 2}"},
+		{"{1, 2, 3} ∖ {1}", "{2, 3}"},
+		{"cho A = {1, 2, 3}\ncho B = {2, 4, 6}\n4 thuộc A ∪ B", "đúng"},
+		{"cho A = {1, 2, 3}\ncho B = {2, 4, 6}\n4 thuộc A ∩ B", "sai"},
+		{"cho A = {1, 2, 3}\ncho B = {2, 4, 6}\n{x : x thuộc [1..6], x thuộc A ∩ B}", "{2}"},
+		{`cho A = {1, 2, 3}` + "\n" + `cho B = {2, 4, 6}` + "\n" + `1 thuộc A \ B và 2 thuộc B \ A`, "sai"},
+		{"1000 thuộc [1..] ∩ [0.., 10]", "đúng"},
+		{"5 thuộc [1..] ∖ [3..]", "sai"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"[0, 1] hợp [2, 3]", "Không thể liệt kê phần tử của '[0,1]' vì đây là tập không đếm được"},
+		{"{1} giao [0, 1]", "Không thể liệt kê phần tử của '[0,1]' vì đây là tập không đếm được"},
+		{"{1} hợp 2", "Không thể dùng phép 'hợp' cho 'Tập Hợp' và 'Số Nguyên'"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+// Words like 'lần' or 'hợp' are keywords only in their context, elsewhere
+// they are part of a name like at the beginning
+func TestKeywordWordsInNames(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho số lần = 3\nsố lần", "3"},
+		{"cho trường hợp = 1\ntrường hợp + 1", "2"},
+		{"cho tu = 2\ntu", "2"},
+		{"cho lan = 2\nlan", "2"},
+		{"cho la = 2\nla", "2"},
+		{"cho là chẵn(n) = n % 2 == 0\nlà chẵn(4)", "đúng"},
+		{"cho là số(x) = x là số nguyên\nlà số(2) và là số(2.5) == sai", "đúng"},
+		{"cho bước nhảy = 2\ncho từ khóa = 3\ncho đến hạn = 4\nbước nhảy + từ khóa + đến hạn", "9"},
+		{"cho giao điểm = 5\n2 * giao điểm", "10"},
+		{"cho số bị trừ = 5\ncho số trừ = 3\nsố bị trừ - số trừ", "2"},
+		{"cho tổ hợp(n, k) = n! / (k! * (n - k)!)\ntổ hợp(5, 2)", "10"},
+		{"cho số lần = 2\ncho s = 0\nlặp 3 lần:\n    s = s + số lần\ns", "6"},
+		{"cho n = 2\ncho s = 0\nlặp i từ 1 đến n bước 1: s = s + i\ns", "3"},
+		{"cho A = {1, 2}\ncho B = {2, 3}\nA hợp B", "{1, 2, 3}"},
+		{"cho A = {1, 2}\ncho B = {2, 3}\nA giao B", "{2}"},
+		{"cho A = {1, 2}\ncho B = {2, 3}\n(A trừ B) hợp [5..6]", "{1, 5, 6}"},
+		{"cho tiếp tuyến = 1\ncho hệ số góc tiếp tuyến = 2\nhệ số góc tiếp tuyến + tiếp tuyến", "3"},
+		{"cho thời điểm khi = 1\ncho n = 0\nkhi n < 3: n = n + thời điểm khi\nn", "3"},
+		{"cho lối thoát = 2\ncho s = 0\nngoài: lặp i từ 1 đến 3:\n    lặp j từ 1 đến 3:\n        nếu j == lối thoát: thoát ngoài\n        s = s + j\ns", "1"},
+		{"cho hàm số = 3\nhàm số + 1", "4"},
+		{"cho hàm bậc hai(x) = x^2\n2 * hàm bậc hai(3)", "18"},
+		{"cho mod = 3\n7 mod mod", "1"},
+		{"cho điểm dừng = 3\ncho s = 0\nlặp i từ 1 đến 5:\n    nếu i > điểm dừng: dừng\n    s = s + i\ns", "6"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestEquality(t *testing.T) {
+	tests := []struct {
+		left  string
+		right string
+		equal bool
+	}{
+		{"2", "2", true},
+		{"2", "3", false},
+		{"2", "2.0", true},
+		{"1/2", "2/4", true},
+		{"1/4", "0.25", true},
+		{"3", "3/1", true},
+		{"1/3", "1/2", false},
+		{"đúng", "sai", false},
+		{"đúng", "1", true},
+		{"sai", "0", true},
+		{"đúng", "2", false},
+		{`"ab"`, `"ab"`, true},
+		{`"ab"`, `"ba"`, false},
+		{"{1, 2}", "{1, 2}", true},
+		{"{1, 2}", "{2, 1}", false},
+		{"{1, 2}", "{1, 3}", false},
+		{"({1, 2} hợp {2})", "{2, 1, 1}", true},
+		{"{ x : x thuộc [1..3] }", "{3, 2, 1}", true},
+	}
+
+	// '!=' is the negation of '==' and both don't depend on the order
+	for _, test := range tests {
+		equal, notEqual := "sai", "đúng"
+		if test.equal {
+			equal, notEqual = "đúng", "sai"
+		}
+		testDisplay(t, test.left+" == "+test.right, equal)
+		testDisplay(t, test.right+" == "+test.left, equal)
+		testDisplay(t, test.left+" != "+test.right, notEqual)
+		testDisplay(t, test.right+" != "+test.left, notEqual)
+	}
+
+	for _, op := range []string{"==", "!="} {
+		testError(t, `1 `+op+` "a"`, "Không thể so sánh 'Số Nguyên' với 'Chuỗi'")
+		testError(t, `"a" `+op+` 1`, "Không thể so sánh 'Chuỗi' với 'Số Nguyên'")
+		testError(t, "[1, 2, 3] "+op+" [1, 2, 3]", "Không thể so sánh 'Mảng' với 'Mảng'")
+	}
+}
+
+func TestRealEqualityHint(t *testing.T) {
+	tests := []struct {
+		input    string
+		teaching bool
+		warnings int
+	}{
+		{"0.1 == 0.1", true, 1},
+		{"0.1 == 0.1", false, 0},
+		{"0.5 != 1", true, 1},
+		{"1 == 1", true, 0},
+		{"1/2 == 2/4", true, 0},
+		{"0.5 < 1", true, 0},
+		{"0.1 == 0.1; 0.2 == 0.2", true, 1},
+	}
+
+	for _, test := range tests {
+		config := NewConfig()
+		config.Teaching = test.teaching
+
+		_, errors := EvalFromInput(test.input, "", object.NewEnvironment(), config)
+		if len(errors.Warnings) != test.warnings {
+			t.Errorf("input %q has wrong number of warnings. want=%d, got=%d",
+				test.input, test.warnings, len(errors.Warnings))
+		}
+	}
+
+	config := NewConfig()
+	config.Teaching = true
+	_, errors := EvalFromInput("0.1 == 0.1", "", object.NewEnvironment(), config)
+	expected := "So sánh '==' giữa các số thực có thể sai do làm tròn, hãy dùng xấp xỉ(a, b)"
+	if errors.Warnings[0].Message != expected {
+		t.Errorf("wrong warning. want=%q, got=%q", expected, errors.Warnings[0].Message)
+	}
+
+	testDisplay(t, "xấp xỉ(0.1 + 0.2, 0.3)", "đúng")
+	testDisplay(t, "xấp xỉ(1, 1.001)", "sai")
+	testDisplay(t, "xấp xỉ(1/3, 0.33333333333)", "đúng")
+	testDisplay(t, "xấp xỉ(ln(0), ln(0))", "đúng")
+	testDisplay(t, "xấp xỉ(ln(0), -ln(0))", "sai")
+	testDisplay(t, "xấp xỉ(1, ln(0))", "sai")
+}
+
+func TestDistanceBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"khoảng_cách({0, 0}, {3, 4})", "5"},
+		{"khoảng_cách({1, 2}, {1, 2})", "0"},
+		{"khoảng_cách({-1, -1}, {2, 3})", "5"},
+		{"khoảng_cách({1, 2, 3}, {3, 5, 9})", "7"},
+		{"xấp xỉ(khoảng_cách({0, 0, 0}, {1/2, 1/2, 1/2})^2, 0.75)", "đúng"},
+		{"khoảng_cách({0.5}, {2})", "1.5"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "khoảng_cách({0, 0}, {1, 2, 3})", "Hai điểm phải có cùng số chiều thay vì 2 và 3")
+	testError(t, `khoảng_cách({0, "a"}, {1, 2})`, "Tọa độ của điểm phải là số thực")
+	testError(t, "khoảng_cách(1, {1, 2})", "Không thể dùng 'Số Nguyên' làm tham số")
+}
+
+func TestAngleBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"sang_radian(180) == Pi", "đúng"},
+		{"sang_độ(Pi) == 180", "đúng"},
+		{"sang_độ(Pi)", "180"},
+		{"sang_độ(Pi/6)", "30"},
+		{"sang_radian(0)", "0"},
+		{"xấp xỉ(sang_radian(90), Pi/2)", "đúng"},
+		{"xấp xỉ(sang_radian(1/2), Pi/360)", "đúng"},
+		{"xấp xỉ(sin(sang_radian(30)), 0.5)", "đúng"},
+		{"sang_độ(sang_radian(45))", "45"},
+		{"sang_radian(180) là Số Thực", "đúng"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, `sang_radian("a")`, "Không thể dùng 'Chuỗi' làm tham số")
+}
+
+func TestIndexing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[10, 20, 30][0]", "10"},
+		{"[10, 20, 30][2]", "30"},
+		{"[10, 20, 30][-1]", "30"},
+		{"[10, 20, 30][-3]", "10"},
+		{"{1, 2, 3}[-2]", "2"},
+		{`"xin chào"[-1]`, `"o"`},
+		{"[1..10][-1]", "10"},
+		{"[1..10, 3][-1]", "10"},
+		{"[1..][5]", "6"},
+		{"cho xs = [1, 2, 3]\nxs[#xs - 1] == xs[-1]", "đúng"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"[10, 20, 30][3]", "Chỉ số 3 vượt quá độ dài 3 của 'Mảng'"},
+		{"[10, 20, 30][-4]", "Chỉ số -4 vượt quá độ dài 3 của 'Mảng'"},
+		{"{1, 2}[-3]", "Chỉ số -3 vượt quá độ dài 2 của 'Tập Hợp'"},
+		{"[1..][-1]", "Chỉ số -1 không hợp lệ cho tập vô hạn"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestSortedSetDisplay(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{ 10 - x : x thuộc [1..5] }", "{5, 6, 7, 8, 9}"},
+		{"{ x : x thuộc {3, 1/2, -1, 2.5} }", "{-1, 1/2, 2.5, 3}"},
+		{`{ x : x thuộc {"b", 2, "a", 1} }`, `{1, 2, "a", "b"}`},
+		{"{3, 1} hợp {2}", "{1, 2, 3}"},
+		{"{5, 4, 3} trừ {4}", "{3, 5}"},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 5; i++ {
+			testDisplay(t, test.input, test.expected)
+		}
+	}
+}
+
+func TestCardinalityBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"sốLượng([])", "0"},
+		{"sốLượng({ x : x thuộc [1..5], x > 10 })", "0"},
+		{"sốLượng({1, 2, 3})", "3"},
+		{"sốLượng([1..10])", "10"},
+		{"sốLượng([1..10, 3])", "4"},
+		{"sốLượng([5..1])", "0"},
+		{"sốLượng([1..10^30])", "1000000000000000000000000000000"},
+		{"sốLượng([1..10] trừ {2, 3})", "8"},
+		{"sốLượng({x^2 | x thuộc [1..4]})", "4"},
+		{"#{ x : x thuộc [1..5], x > 10 }", "0"},
+		{"#{1, 2, 3}", "3"},
+		{"#[1, 2, 3]", "3"},
+		{"#[1..10^30]", "1000000000000000000000000000000"},
+		{`#"abc"`, "3"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"sốLượng([0, 1])", "Tập không đếm được nên không có số lượng phần tử hữu hạn"},
+		{"sốLượng([1..])", "Tập vô hạn không có số lượng phần tử hữu hạn"},
+		{"sốLượng(5)", "Không thể dùng 'Số Nguyên' làm tham số"},
+		{"#[1..]", "Tập vô hạn không có số lượng phần tử hữu hạn"},
+		{"#[0, 1]", "Tập không đếm được nên không có số lượng phần tử hữu hạn"},
+		{"#5", "Không thể lấy độ dài của 'Số Nguyên'"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestMethodCall(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[3, 1, 2].sắp_xếp()", "[1, 2, 3]"},
+		{"[3, 1, 2].độ_dài()", "3"},
+		{"[3, 1, 2].sắp_xếp().độ_dài()", "3"},
+		{"cho xs = [3, 1, 2]\nxs.sắp_xếp()\nxs", "[3, 1, 2]"},
+		{"{2, 1}.sắp_xếp()", "{1, 2}"},
+		{"[1..10].số_lượng()", "10"},
+		{`"xin chào".độ_dài()`, "8"},
+		{`"xin chào".bắt_đầu_bằng("xin")`, "đúng"},
+		{`"a-b".thay_thế("-", "+")`, `"a+b"`},
+		{"cho f(x) = x + 1\ncho g(x) = 2x\nf.g(3)", "7"},
+		{`cho s = "b"` + "\n" + `"a".s`, `"ab"`},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	tests := []struct {
+		input  string
+		stdout string
+		stderr string
+	}{
+		{`in_lỗi("sai rồi")`, "", "sai rồi\n"},
+		{`in_lỗi("x =", 1/2, [1, 2, 3])`, "", "x = 1/2 [1, 2, 3]\n"},
+		{`xuất "kết quả", 5` + "\n" + `in_lỗi("lỗi", 1)`, "kết quả 5 \n", "lỗi 1\n"},
+		{"in_lỗi()", "", "\n"},
+	}
+
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		config := NewConfig()
+		config.Stdout = &stdout
+		config.Stderr = &stderr
+
+		value, errors := EvalFromInput(test.input, "", object.NewEnvironment(), config)
+		if errors.NotEmpty() {
+			t.Fatalf("input %q has errors: \n%s", test.input, errors)
+		}
+		if value != NO_PRINT {
+			t.Errorf("input %q shouldn't have a value, got=%s", test.input, value.Display())
+		}
+		if stdout.String() != test.stdout {
+			t.Errorf("input %q has wrong stdout. want=%q, got=%q", test.input, test.stdout, stdout.String())
+		}
+		if stderr.String() != test.stderr {
+			t.Errorf("input %q has wrong stderr. want=%q, got=%q", test.input, test.stderr, stderr.String())
+		}
+	}
+}
+
+func TestOpenInterval(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(0, 1)", "(0,1)"},
+		{"[0, 1)", "[0,1)"},
+		{"(0, 1]", "(0,1]"},
+		{"(0 + 1, 2 * 3)", "(1,6)"},
+		{"0 thuộc (0, 1)", "sai"},
+		{"1 thuộc (0, 1)", "sai"},
+		{"0.5 thuộc (0, 1)", "đúng"},
+		{"0 thuộc [0, 1)", "đúng"},
+		{"1 thuộc [0, 1)", "sai"},
+		{"1 thuộc (0, 1]", "đúng"},
+		{"3 ∈ [1, 3)", "sai"},
+		{"3 ∈ [1, 3]", "đúng"},
+		{"1 ∈ (1, 3]", "sai"},
+		{"với mỗi x ∈ [1..3): x", "2"},
+		{"(1..5)", "[2..4]"},
+		{"[1..5)", "[1..4]"},
+		{"(1..5]", "[2..5]"},
+		{"[1..10, 3)", "[1..7,3]"},
+		{"sốLượng([1..9, 3))", "3"},
+		{"5 thuộc [1..5)", "sai"},
+		{"cho s = 0; với mỗi x thuộc (1..5): s = s + x\ns", "9"},
+		{"[0, 1) + [1, 2]", "[0,2]"},
+		{"(0, 1) + [0, 1)", "[0,1)"},
+		{"1 thuộc (0, 1) + (1, 2)", "sai"},
+		{"[0, 5] + (1, 2)", "[0,5]"},
+		{"(1 + 2) * 3", "9"},
+		{"(cho x = 2; x + 1)", "3"},
+		{"(\n    cho y = 2\n    y * 5\n)", "10"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestComputedIntervalBound(t *testing.T) {
+	counter := "cho đếm = 0\nhàm g(n):\n    đếm = đếm + 1\n    trả về n\n"
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho f(n) = n * 2\n[0..f(3)]", "[0..6]"},
+		{"cho f(n) = n * 2\n[0, f(3)]", "[0,6]"},
+		{"cho f(n) = n * 2\n[f(1)..f(5), f(2)]", "[2..10,4]"},
+		{"cho f(n) = n * 2\nsốLượng([1..f(5)))", "9"},
+		{counter + "cho s = 0\nvới mỗi x thuộc [1..g(4)]: s = s + x\ns + 100 * đếm", "110"},
+		{counter + "tổng(x thuộc [g(1)..g(3)], x) + đếm", "8"},
+		{counter + "sốLượng({ x : x thuộc [1..g(3)], y thuộc [1..g(2)] }) + đếm", "7"},
+		{"{ x * y : x thuộc [1..3], y thuộc [1..x] }", "{1, 2, 3, 4, 6, 9}"},
+		{"cho s = 0\nvới mỗi x thuộc [1..3], y thuộc [x..3]: s = s + 1\ns", "6"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho h() = \"a\"\n[1..h()]", "Không thể dùng 'Chuỗi' làm chặn trên"},
+		{"cho h() = \"a\"\n[h()..3]", "Không thể dùng 'Chuỗi' làm chặn dưới"},
+		{"cho h() = \"a\"\n[1..5, h()]", "Không thể dùng 'Chuỗi' làm bước nhảy"},
+		{"cho h() = 0\n[1..5, h()]", "Bước nhảy của khoảng phải khác 0"},
+		{"cho h() = \"a\"\n[1, h()]", "Không thể dùng 'Chuỗi' làm chặn trên"},
+		{"cho h() = \"a\"\n(h(), 2)", "Không thể dùng 'Chuỗi' làm chặn dưới"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestIndexAssign(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho xs = [1, 2, 3]\nxs[1] = 9\nxs", "[1, 9, 3]"},
+		{"cho xs = [1, 2, 3]\nxs[-1] = 0\nxs", "[1, 2, 0]"},
+		{"cho xs = [1, 2, 3]\nxs[0] = xs[0] + 10", "11"},
+		{"cho xs = [1, 2, 3]\ncho ys = xs\nys[0] = 5\nxs", "[5, 2, 3]"},
+		{"cho xs = [[1, 2, 3], 4, 5]\nxs[0][2] = 7\nxs", "[[1, 2, 7], 4, 5]"},
+		{"cho p = {1, 2}\np[0] = 3\np", "{3, 2}"},
+		{"cho xs = [1, 2, 3]\ncho ys = sao chép(xs)\nys[0] = 5\nxs", "[1, 2, 3]"},
+		{"cho xs = [0, 0, 0]\nvới mỗi i thuộc [0..2]: xs[i] = i^2\nxs", "[0, 1, 4]"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho xs = [1, 2, 3]\nxs[3] = 4", "Chỉ số 3 vượt quá độ dài 3 của 'Mảng'"},
+		{"cho xs = [1, 2, 3]\nxs[-4] = 4", "Chỉ số -4 vượt quá độ dài 3 của 'Mảng'"},
+		{`cho s = "abc"` + "\n" + `s[0] = "x"`, "Không thể gán phần tử cho 'Chuỗi'"},
+		{"cho A = [1..5]\nA[0] = 4", "Không thể gán phần tử cho 'Tập Hợp'"},
+		{"cho xs = [1, 2, 3]\nxs[0.5] = 4", "Chỉ số phải là một 'Số Nguyên' thay vì 'Số Thực'"},
+	}
+
+	for _, test := range errorTests {
+		testError(t, test.input, test.expected)
+	}
+}
+
+func TestStrictAssign(t *testing.T) {
+	tests := []struct {
+		input    string
+		strict   bool
+		expected string
+	}{
+		{"x = 5\nx", false, "5"},
+		{"cho x = 1\nx = 5\nx", false, "5"},
+		{"cho x = 1\nx = 5\nx", true, "5"},
+		{"cho x = 1\nnếu đúng:\n    x = 2\nx", true, "2"},
+		{"cho s = 0\nvới mỗi i thuộc [1..3]:\n    t = i\n    s = s + t\ns", false, "6"},
+		{"cho s = 0\nvới mỗi i thuộc [1..3]:\n    cho t = i\n    s = s + t\ns", true, "6"},
+		{"hàm f(x):\n    x = x + 1\n    x\nf(1)", true, "2"},
+		{"cho n = 0\ncho tăng = hàm() = (n = n + 1; n)\ntăng()\ntăng()", true, "2"},
+		{"hàm f():\n    y = 3\n    y\nf()", false, "3"},
+	}
+
+	for _, test := range tests {
+		config := NewConfig()
+		config.Strict = test.strict
+
+		value, errors := EvalFromInput(test.input, "", object.NewEnvironment(), config)
+		if errors.NotEmpty() {
+			t.Fatalf("input %q has errors: \n%s", test.input, errors)
+		}
+		if value.Display() != test.expected {
+			t.Errorf("input %q has wrong value. want=%s, got=%s", test.input, test.expected, value.Display())
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho đếm = 0\ndem = đếm + 1", "Biến 'dem' chưa được khai báo, hãy dùng 'cho dem = ...'"},
+		{"hàm f():\n    y = 3\n    y\nf()", "Biến 'y' chưa được khai báo, hãy dùng 'cho y = ...'"},
+		{"với mỗi i thuộc [1..3]:\n    t = i", "Biến 't' chưa được khai báo, hãy dùng 'cho t = ...'"},
+	}
+
+	for _, test := range errorTests {
+		config := NewConfig()
+		config.Strict = true
+		_, errors := EvalFromInput(test.input, "", object.NewEnvironment(), config)
+		if len(errors.EvalErrors) == 0 || errors.EvalErrors[0].Message != test.expected {
+			t.Errorf("input %q expected error %q, got %v", test.input, test.expected, errors.EvalErrors)
+		}
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected errorhandler.ErrorCode
+	}{
+		{"1 @ 2", errorhandler.INVALID_CHARACTER},
+		{`"abc`, errorhandler.UNTERMINATED},
+		{"(* chú thích", errorhandler.UNTERMINATED},
+		{"0b102", errorhandler.INVALID_NUMBER},
+		{"1e+", errorhandler.INVALID_NUMBER},
+		{"f(1, 2", errorhandler.UNEXPECTED_TOKEN},
+		{"cho = 1", errorhandler.UNEXPECTED_TOKEN},
+		{"1 +", errorhandler.INVALID_SYNTAX},
+		{"nếu đúng:", errorhandler.MISSING_OPERAND},
+		{"cho f(x, x) = x", errorhandler.DUPLICATE_PARAM},
+		{"nếu đúng:\n    1\n        2", errorhandler.INVALID_INDENT},
+		{"x + 1", errorhandler.UNDEFINED_IDENT},
+		{"cho a = 1\ncho a = 2", errorhandler.ALREADY_DEFINED},
+		{"cho f(x) = x\nf(1, 2)", errorhandler.ARGUMENT_COUNT},
+		{`1 + "a"`, errorhandler.INVALID_TYPE},
+		{"1 / 0", errorhandler.ZERO_DIVISION},
+		{"[1, 2, 3][5]", errorhandler.INDEX_OUT_OF_RANGE},
+		{"(-1)!", errorhandler.INVALID_VALUE},
+		{"dừng", errorhandler.MISPLACED},
+		{"căn()", errorhandler.ARGUMENT_COUNT},
+		{`sắp_xếp(1)`, errorhandler.BUILTIN_ERROR},
+	}
+
+	for _, test := range tests {
+		_, errors := EvalFromInput(test.input, "", object.NewEnvironment())
+
+		var code errorhandler.ErrorCode
+		switch {
+		case len(errors.LexerErrors) > 0:
+			code = errors.LexerErrors[0].Code
+		case len(errors.ParserErrors) > 0:
+			code = errors.ParserErrors[0].Code
+		case len(errors.EvalErrors) > 0:
+			code = errors.EvalErrors[0].Code
+		default:
+			t.Fatalf("input %q expected error %s, got none", test.input, test.expected)
+		}
+		if code != test.expected {
+			t.Errorf("input %q has wrong error code. want=%s, got=%s", test.input, test.expected, code)
+		}
+	}
+}
+
+func TestOutputWriter(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`xuất "xin chào"`, "xin chào \n"},
+		{"xuất 1, 1/2, [1, 2, 3]", "1 1/2 [1, 2, 3] \n"},
+		{"với mỗi i thuộc [1..3]: xuất i", "1 \n2 \n3 \n"},
+		{"cho f(x) = (xuất x; x^2)\nf(2) + f(3)", "2 \n3 \n"},
+		{"cho x = 5", ""},
+	}
+
+	for _, test := range tests {
+		var stdout bytes.Buffer
+		config := NewConfig()
+		config.Stdout = &stdout
+
+		_, errors := EvalFromInput(test.input, "", object.NewEnvironment(), config)
+		if errors.NotEmpty() {
+			t.Fatalf("input %q has errors: \n%s", test.input, errors)
+		}
+		if stdout.String() != test.expected {
+			t.Errorf("input %q has wrong output. want=%q, got=%q", test.input, test.expected, stdout.String())
+		}
+	}
+
+	// each session writes to its own writer
+	var first, second bytes.Buffer
+	firstConfig, secondConfig := NewConfig(), NewConfig()
+	firstConfig.Stdout, secondConfig.Stdout = &first, &second
+
+	EvalFromInput("xuất 1", "", object.NewEnvironment(), firstConfig)
+	EvalFromInput("xuất 2", "", object.NewEnvironment(), secondConfig)
+	if first.String() != "1 \n" || second.String() != "2 \n" {
+		t.Errorf("sessions share output, got %q and %q", first.String(), second.String())
+	}
+}
+
+func TestProgramValue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cho a = 2\ncho b = 3\na * b", "6"},
+		{"cho xs = [1, 2, 3]\nxs[0] = 5\nxs", "[5, 2, 3]"},
+		{"cho f(x) = x^2\n\nf(4)\n", "16"},
+		{"cho s = 0\nvới mỗi i thuộc [1..4]:\n    s = s + i\ns", "10"},
+		{"cho x = 5", "5"},
+	}
+
+	for _, test := range tests {
+		value, errors := EvalFromInput(test.input, "", object.NewEnvironment())
+		if errors.NotEmpty() {
+			t.Fatalf("input %q has errors: \n%s", test.input, errors)
+		}
+		if value.Display() != test.expected {
+			t.Errorf("input %q has wrong value. want=%q, got=%q", test.input, test.expected, value.Display())
+		}
+	}
+
+	// programs ending with an output statement have nothing left to print
+	for _, input := range []string{"", "1 + 1\nxuất 2", "cho x = 1\nnếu x > 0: xuất x"} {
+		var stdout bytes.Buffer
+		config := NewConfig()
+		config.Stdout = &stdout
+
+		value, _ := EvalFromInput(input, "", object.NewEnvironment(), config)
+		if value != NO_PRINT {
+			t.Errorf("input %q should have no value, got=%q", input, value.Display())
+		}
+	}
+}
+
+func TestExponentAssociativity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"2^3^2", "512"},
+		{"(2^3)^2", "64"},
+		{"2^2^2^2", "65536"},
+		{"2^1^2^3^4", "2"},
+		{"3 * 2^3^2", "1536"},
+		{"2^3^2 + 1", "513"},
+		{"2^3^2 == 512", "đúng"},
+		{"4^3^0", "4"},
+		{"2^3^2 / 2^9", "1"},
+		{"cho a = 3\n2^a^2", "512"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestSignBeforeExponent(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"-2^2", "-4"},
+		{"-2^2 == -4", "đúng"},
+		{"(-2)^2", "4"},
+		{"(-2)^2 == 4", "đúng"},
+		{"cho x = 3\n-x^2", "-9"},
+		{"cho x = 2\n-2x^2", "-8"},
+		{"-2^-2", "-1/4"},
+		{"2 * -3^2", "-18"},
+		{"1 - -2^2", "5"},
+		{"--2^2", "4"},
+		{"-2 * 3", "-6"},
+		{"-3!", "-6"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+}
+
+func TestNegativeBase(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(-2.0)^3", "-8"},
+		{"(-2)^2.0", "4"},
+		{"(-1.5)^2", "2.25"},
+		{"(-2.5)^-1", "-0.4"},
+		{"4^(1/2)", "2"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, "(-2)^0.5", "Không thể lấy lũy thừa không nguyên 0.5 của số âm -2")
+	testError(t, "(-8)^(1/3)", "Không thể lấy lũy thừa không nguyên 1/3 của số âm -8")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+	}{
+		{"xuất 1\nnếu sai: xuất 1/0", "Không thể chia cho 0"},
+		{"cho f(x) = x + (-8)^(1/3)", "Không thể lấy lũy thừa không nguyên 1/3 của số âm -8"},
+		{"với mỗi i thuộc [1..3]: xuất i + 7 % (2 - 2)", "Không thể chia cho 0"},
+	}
+
+	for _, test := range tests {
+		var stdout bytes.Buffer
+		config := NewConfig()
+		config.Stdout = &stdout
+		config.Validate = true
+
+		_, errors := EvalFromInput(test.input, "", object.NewEnvironment(), config)
+		if len(errors.EvalErrors) == 0 {
+			t.Fatalf("input %q expected error %q, got none", test.input, test.message)
+		}
+		if errors.EvalErrors[0].Message != test.message {
+			t.Errorf("input %q has wrong error. want=%q, got=%q", test.input, test.message, errors.EvalErrors[0].Message)
+		}
+		if stdout.Len() != 0 {
+			t.Errorf("input %q should not run, got output %q", test.input, stdout.String())
+		}
+	}
+
+	// a reversed constant interval is only warned, the program still runs
+	var stdout bytes.Buffer
+	config := NewConfig()
+	config.Stdout = &stdout
+	config.Validate = true
+
+	_, errors := EvalFromInput("cho a = [5, 1]\nxuất 1", "", object.NewEnvironment(), config)
+	if errors.NotEmpty() || len(errors.Warnings) != 1 {
+		t.Fatalf("expected one warning, got: \n%s", errors)
+	}
+	if message := errors.Warnings[0].Message; message != "Khoảng [5,1] luôn rỗng vì cận dưới lớn hơn cận trên" {
+		t.Errorf("wrong warning %q", message)
+	}
+	if stdout.String() != "1 \n" {
+		t.Errorf("wrong output %q", stdout.String())
+	}
+
+	// without the flag an unreached 1/0 is never evaluated
+	_, errors = EvalFromInput("nếu sai: 1/0", "", object.NewEnvironment())
+	if errors.NotEmpty() || len(errors.Warnings) != 0 {
+		t.Errorf("expected no errors without validation, got: \n%s", errors)
+	}
+}
+
+func TestComplex(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"3i", "3i"},
+		{"1i", "i"},
+		{"-1i", "-i"},
+		{"-3i", "-3i"},
+		{"2.5i", "2.5i"},
+		{"0i", "0"},
+		{"2 + 3i", "2 + 3i"},
+		{"2 - 3i", "2 - 3i"},
+		{"2 - 1i", "2 - i"},
+		{"3i + 2", "2 + 3i"},
+		{"(2 + 3i) - 3i", "2"},
+		{"(1 + 1i) * (1 - 1i)", "2"},
+		{"(1 + 1i) * (1 - 1i) == 2", "đúng"},
+		{"2 == (1 + 1i) * (1 - 1i)", "đúng"},
+		{"(1 + 2i) != (1 - 2i)", "đúng"},
+		{"1i == I", "đúng"},
+		{"1i * 1i", "-1"},
+		{"(1 + 1i) / (1 - 1i)", "i"},
+		{"mô_đun(3 + 4i)", "5"},
+		{"mô_đun(-4i)", "4"},
+		{"mô_đun(-5)", "5"},
+		{"mô_đun(-1/2)", "1/2"},
+		// 2i stays imaginary even with a variable named i
+		{"tổng(i, 1, 3, 2i)", "6i"},
+		{"tổng(i, 1, 3, i * 1i)", "6i"},
+		{"cho i = 5\n3i", "3i"},
+		{"cho i = 5\n3 * i", "15"},
+		// only exactly 1 is left out
+		{"1.00000001i", "1.00000001i"},
+		{"1 + 0.99999999i", "1 + 0.99999999i"},
+		{"(1 + 1i) / 3", "1/3 + 1/3i"},
+	}
+
+	for _, test := range tests {
+		testDisplay(t, test.input, test.expected)
+	}
+
+	testError(t, `mô_đun("a")`, "Không thể dùng 'Chuỗi' làm tham số")
+}
+
+// enoughOutput is raised by lineLimitWriter to stop a program that prints
+// forever
+type enoughOutput struct{}
+
+type lineLimitWriter struct {
+	bytes.Buffer
+	lines int
+}
+
+func (w *lineLimitWriter) Write(p []byte) (int, error) {
+	n, err := w.Buffer.Write(p)
+	if strings.Count(w.String(), "\n") >= w.lines {
+		panic(enoughOutput{})
+	}
+	return n, err
+}
+
+// The examples are run as they are shipped, 'là' at the start of a name like
+// 'là ngtố' must stay a name
+func TestExamples(t *testing.T) {
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{"is_even.vv", "2 là số chẵn \n4 là số chẵn \n6 là số chẵn \n"},
+		{"check_prime.vv", "2 \n3 \n5 \n7 \n11 \n13 \n"},
+	}
+
+	for _, test := range tests {
+		input, err := os.ReadFile(filepath.Join("..", "..", "examples", test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stdout := &lineLimitWriter{lines: strings.Count(test.expected, "\n")}
+		config := NewConfig()
+		config.Stdout = stdout
+
+		func() {
+			defer func() {
+				if r := recover(); r != nil && r != (enoughOutput{}) {
+					panic(r)
+				}
+			}()
+			if _, errors := EvalFromInput(string(input), test.file, object.NewEnvironment(), config); errors.NotEmpty() {
+				t.Errorf("%s has errors: \n%s", test.file, errors)
+			}
+		}()
+
+		if stdout.String() != test.expected {
+			t.Errorf("%s has wrong output. want=%q, got=%q", test.file, test.expected, stdout.String())
+		}
+	}
+}
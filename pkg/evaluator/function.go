@@ -1,27 +1,27 @@
 package evaluator
 
 import (
-	"fmt"
 	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/object"
 )
 
 func (ev *Evaluator) evalCallExpression(call *ast.CallExpression) object.Object {
-	fn := ev.Eval(call.Function)
+	if result, ok := ev.evalMethodCall(call); ok {
+		return result
+	}
+	if result, ok := ev.evalSpecialForm(call); ok {
+		return result
+	}
+	return ev.applyCall(call, ev.Eval(call.Function))
+}
+
+func (ev *Evaluator) applyCall(call *ast.CallExpression, fn object.Object) object.Object {
 	args := ev.evalExpressions(call.Arguments)
 
 	switch fn := fn.(type) {
 	case *object.Function:
-		res := ev.applyFunction(fn, args)
-		args = []object.Object{res}
-
-		for fn.LeftCompose != nil {
-			fn = fn.LeftCompose
-			res = ev.applyFunction(fn, args)
-			args = []object.Object{res}
-		}
-
-		return res
+		return ev.callFunction(fn, args...)
 
 	default:
 		if len(call.Arguments) == 1 {
@@ -29,32 +29,50 @@ func (ev *Evaluator) evalCallExpression(call *ast.CallExpression) object.Object
 			return ev.evalMultiplication(fn, right)
 		}
 
-		return ev.runtimeError("Biểu thức không hợp lệ")
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errorhandler.InvalidExpression)
 	}
 }
 
-func (ev *Evaluator) applyFunction(fn *object.Function, args []object.Object) object.Object {
-	env := object.NewEnclosedEnvironment(ev.Env)
+// callFunction applies fn and then every function composed to its left
+func (ev *Evaluator) callFunction(fn *object.Function, args ...object.Object) object.Object {
+	res := ev.applyFunction(fn, args)
+	args = []object.Object{res}
+
+	for fn.LeftCompose != nil {
+		fn = fn.LeftCompose
+		res = ev.applyFunction(fn, args)
+		args = []object.Object{res}
+	}
+
+	return res
+}
 
+func (ev *Evaluator) applyFunction(fn *object.Function, args []object.Object) object.Object {
 	if fn.Builtin != nil {
-		res := fn.Builtin(args...)
-		if err, ok := res.(*object.Error); ok {
-			return ev.runtimeError(err.Message)
-		}
-		if err, ok := res.(*object.ArgumentError); ok {
-			errMsg := fmt.Sprintf("Cần %d tham số thay vì %d", err.Expected, err.Received)
-			return ev.runtimeError(errMsg)
-		}
+		return ev.builtinResult(fn.Builtin(args...))
+	}
+	if fn.HigherOrder != nil {
+		return ev.builtinResult(fn.HigherOrder(ev.callFunction, args...))
+	}
+	if fn.Writer != nil {
+		out := object.Output{Stdout: ev.Config.Stdout, Stderr: ev.Config.Stderr}
+		return ev.builtinResult(fn.Writer(out, args...))
+	}
+	if fn.Evaluating != nil {
+		return ev.builtinResult(fn.Evaluating(ev.evalSource, args...))
+	}
 
-		return res
+	// functions see the environment they are declared in
+	outer := fn.Env
+	if outer == nil {
+		outer = ev.Env
 	}
+	env := object.NewEnclosedEnvironment(outer)
 
 	if len(args) != len(fn.Params) {
-		errMsg := fmt.Sprintf(
-			"'%s' cần %d tham số thay vì %d",
-			fn.Ident.Value, len(fn.Params), len(args))
+		errMsg := errorhandler.FunctionArgumentCount.With(fn.Name(), len(fn.Params), len(args))
 
-		return ev.runtimeError(errMsg)
+		return ev.runtimeError(errorhandler.ARGUMENT_COUNT, errMsg)
 	}
 
 	for index, param := range fn.Params {
@@ -62,9 +80,24 @@ func (ev *Evaluator) applyFunction(fn *object.Function, args []object.Object) ob
 	}
 
 	val := ev.Eval(fn.Body, env)
+	if isLoopControl(val) {
+		return ev.loopControlOutsideLoop(val)
+	}
 	return ev.unwrapImply(val)
 }
 
+func (ev *Evaluator) builtinResult(res object.Object) object.Object {
+	if err, ok := res.(*object.Error); ok {
+		return ev.runtimeError(errorhandler.BUILTIN_ERROR, err.Message)
+	}
+	if err, ok := res.(*object.ArgumentError); ok {
+		errMsg := errorhandler.ArgumentCount.With(err.Expected, err.Received)
+		return ev.runtimeError(errorhandler.ARGUMENT_COUNT, errMsg)
+	}
+
+	return res
+}
+
 func (ev *Evaluator) unwrapImply(obj object.Object) object.Object {
 	if imply, ok := obj.(*object.Imply); ok {
 		return ev.unwrapImply(imply.Value)
@@ -1,7 +1,10 @@
 package evaluator
 
 import (
-	"fmt"
+	"math/big"
+	"strings"
+	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/object"
 	"vanvo/pkg/token"
 )
@@ -39,11 +42,19 @@ func (ev *Evaluator) evalInfixExpression(
 	case token.Hat:
 		return ev.evalExponent(left, right)
 
+	case token.Ampersand, token.Bar, token.Xor, token.LessLess, token.GreaterGreater:
+		return ev.evalBitwise(operator, left, right)
+
+	case token.Union, token.Intersect, token.SetMinus:
+		return ev.evalSetAlgebra(operator, left, right)
+
 	case token.Equal:
+		ev.realEqualityHint(operator, left, right)
 		return ev.evalEquality(left, right)
 
 	case token.NotEqual:
-		return ev.evalEquality(left, right).Not()
+		ev.realEqualityHint(operator, left, right)
+		return ev.evalNotEqual(left, right)
 
 	case token.Less:
 		return ev.evalLess(left, right)
@@ -63,12 +74,6 @@ func (ev *Evaluator) evalInfixExpression(
 		}
 		return ev.evalEquality(left, right)
 
-	case token.And:
-		return ev.evalAnd(left, right)
-
-	case token.Or:
-		return ev.evalOr(left, right)
-
 	case token.Belong:
 		return ev.evalBelong(left, right)
 	}
@@ -77,7 +82,7 @@ func (ev *Evaluator) evalInfixExpression(
 }
 
 func (ev *Evaluator) evalAddition(left, right object.Object) object.Object {
-	errMsg := fmt.Sprintf("Không thể cộng '%v' với '%v'", left.Type(), right.Type())
+	errMsg := errorhandler.CannotAdd.With(left.Type(), right.Type())
 
 	if left, ok := left.(object.Additive); ok {
 		value := left.Add(right)
@@ -89,11 +94,11 @@ func (ev *Evaluator) evalAddition(left, right object.Object) object.Object {
 		}
 	}
 
-	return ev.runtimeError(errMsg)
+	return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
 }
 
 func (ev *Evaluator) evalSubtraction(left, right object.Object) object.Object {
-	errMsg := fmt.Sprintf("Không thể trừ '%v' với '%v'", left.Type(), right.Type())
+	errMsg := errorhandler.CannotSubtract.With(left.Type(), right.Type())
 
 	if left, ok := left.(object.Subtractive); ok {
 		value := left.Subtract(right)
@@ -105,11 +110,11 @@ func (ev *Evaluator) evalSubtraction(left, right object.Object) object.Object {
 		}
 	}
 
-	return ev.runtimeError(errMsg)
+	return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
 }
 
 func (ev *Evaluator) evalMultiplication(left, right object.Object) object.Object {
-	errMsg := fmt.Sprintf("Không thể nhân '%v' với '%v'", left.Type(), right.Type())
+	errMsg := errorhandler.CannotMultiply.With(left.Type(), right.Type())
 
 	if left, ok := left.(object.Multiplicative); ok {
 		value := left.Multiply(right)
@@ -121,47 +126,56 @@ func (ev *Evaluator) evalMultiplication(left, right object.Object) object.Object
 		}
 	}
 
-	return ev.runtimeError(errMsg)
+	return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
 }
 
 func (ev *Evaluator) evalDivision(left, right object.Object) object.Object {
-	errMsg := fmt.Sprintf("Không thể chia '%v' với '%v'", left.Type(), right.Type())
+	errMsg := errorhandler.CannotDivide.With(left.Type(), right.Type())
 
 	if left, ok := left.(object.Division); ok {
 		value := left.Divide(right)
+		_, isInt := right.(*object.Int)
+		if quo, ok := value.(*object.Quotient); ok && isInt && quo.IsRepeatingDecimal() {
+			hint := errorhandler.RepeatingDecimalHint.With(quo.Display(), quo.Display())
+			ev.hint("repeating-decimal", hint)
+		}
 		return ev.someObject(value, errMsg)
 	}
 
-	return ev.runtimeError(errMsg)
+	return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
 }
 
 func (ev *Evaluator) evalModulo(left, right object.Object) object.Object {
-	errMsg := fmt.Sprintf("Không thể chia lấy dư '%v' với '%v'", left.Type(), right.Type())
+	errMsg := errorhandler.CannotModulo.With(left.Type(), right.Type())
 
 	if left, ok := left.(object.Modulo); ok {
 		value := left.Mod(right)
 		return ev.someObject(value, errMsg)
 	}
 
-	return ev.runtimeError(errMsg)
+	return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
 }
 
 func (ev *Evaluator) evalDotProduct(left, right object.Object) object.Object {
-	errMsg := fmt.Sprintf("Không thể . '%v' với '%v'", left.Type(), right.Type())
+	errMsg := errorhandler.CannotCompose.With(left.Type(), right.Type())
 
 	if left, ok := left.(object.DotProduct); ok {
 		value := left.Dot(right)
 		return ev.someObject(value, errMsg)
 	}
 
-	return ev.runtimeError(errMsg)
+	return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
 }
 
 func (ev *Evaluator) evalExponent(left, right object.Object) object.Object {
-	errMsg := fmt.Sprintf("Không thể mũ '%v' với '%v'", left.Type(), right.Type())
+	errMsg := errorhandler.CannotPower.With(left.Type(), right.Type())
 
 	if left, ok := left.(object.Exponential); ok {
 		value := left.Power(right)
+		if value == object.NOT_REAL {
+			errMsg := errorhandler.NegativeBasePower.With(right.Display(), left.Display())
+			return ev.runtimeError(errorhandler.INVALID_VALUE, errMsg)
+		}
 		return ev.someObject(value, errMsg)
 	}
 	if left, ok := left.(object.Set); ok {
@@ -170,20 +184,51 @@ func (ev *Evaluator) evalExponent(left, right object.Object) object.Object {
 		}
 	}
 
-	return ev.runtimeError(errMsg)
+	return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
+}
+
+// MaxShift is the largest count of a << n, the result has n more bits
+const MaxShift = 1_000_000
+
+func (ev *Evaluator) evalBitwise(operator token.Token, left, right object.Object) object.Object {
+	errMsg := errorhandler.CannotBitwise.With(string(operator.Literal), left.Type(), right.Type())
+
+	bitwise, ok := left.(object.Bitwise)
+	if !ok {
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
+	}
+
+	var value object.Object
+	switch operator.Type {
+	case token.Ampersand:
+		value = bitwise.BitAnd(right)
+	case token.Bar:
+		value = bitwise.BitOr(right)
+	case token.Xor:
+		value = bitwise.BitXor(right)
+	case token.LessLess, token.GreaterGreater:
+		if right, ok := right.(*object.Int); ok && right.Value.Sign() < 0 {
+			return ev.runtimeError(errorhandler.INVALID_VALUE, errorhandler.NegativeShift)
+		}
+		if right, ok := right.(*object.Int); ok && operator.Type == token.LessLess &&
+			right.Value.Cmp(big.NewInt(MaxShift)) > 0 {
+			errMsg := errorhandler.ShiftTooLarge.With(MaxShift)
+			return ev.runtimeError(errorhandler.INVALID_VALUE, errMsg)
+		}
+		if operator.Type == token.LessLess {
+			value = bitwise.ShiftLeft(right)
+		} else {
+			value = bitwise.ShiftRight(right)
+		}
+	}
+	return ev.someObject(value, errMsg)
 }
 
 func (ev *Evaluator) evalUnion(left, right object.Set) object.Set {
 	if left, isInterval := left.(*object.RealInterval); isInterval {
 		if right, isInterval := right.(*object.RealInterval); isInterval {
-			if right.Lower.Less(left.Lower) == TRUE {
-				left, right = right, left
-			}
-			if right.Less(left).Value {
-				return left
-			}
-			if !left.Upper.Less(right.Lower).Value {
-				return &object.RealInterval{Lower: left.Lower, Upper: right.Upper}
+			if merged := mergeIntervals(left, right); merged != nil {
+				return merged
 			}
 		}
 	}
@@ -194,9 +239,100 @@ func (ev *Evaluator) evalUnion(left, right object.Set) object.Set {
 		}
 	}
 
+	if left, isArray := left.(*object.Array); isArray {
+		if right, isArray := right.(*object.Array); isArray {
+			data := append([]object.Object{}, left.Data...)
+			return &object.Array{Data: append(data, right.Data...)}
+		}
+	}
+
 	return &object.UnionSet{Left: left, Right: right}
 }
 
+// evalSetAlgebra gives the elements of 'hợp', 'giao' and 'trừ' without
+// duplicates, the result stays symbolic only when it's infinite
+func (ev *Evaluator) evalSetAlgebra(operator token.Token, left, right object.Object) object.Object {
+	_, ok1 := left.(object.Set)
+	_, ok2 := right.(object.Set)
+	if !ok1 || !ok2 {
+		errMsg := errorhandler.CannotOperate.With(string(operator.Literal), left.Type(), right.Type())
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
+	}
+	for _, set := range []object.Object{left, right} {
+		if countable, ok := set.(object.CountableSet); !ok || !countable.IsCountable() {
+			errMsg := errorhandler.CannotListUncountable.With(set.Display())
+			return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
+		}
+	}
+	leftSet, rightSet := left.(object.CountableSet), right.(object.CountableSet)
+	leftFinite, rightFinite := !object.IsInfinite(leftSet), !object.IsInfinite(rightSet)
+
+	switch operator.Type {
+	case token.Union:
+		if leftFinite && rightFinite {
+			return collectElements(nil, leftSet, rightSet)
+		}
+		return &object.UnionSet{Left: leftSet, Right: rightSet}
+
+	case token.Intersect:
+		if leftFinite {
+			return collectElements(rightSet.Contain, leftSet)
+		}
+		if rightFinite {
+			return collectElements(leftSet.Contain, rightSet)
+		}
+		return &object.IntersectionSet{Left: leftSet, Right: rightSet}
+
+	default:
+		if leftFinite {
+			return collectElements(func(obj object.Object) *object.Boolean {
+				return rightSet.Contain(obj).Not()
+			}, leftSet)
+		}
+		return &object.DiffSet{Left: leftSet, Right: rightSet}
+	}
+}
+
+// collectElements puts the elements of finite sets passing the filter into a
+// new sorted set, each value is kept once
+func collectElements(filter func(object.Object) *object.Boolean, sets ...object.CountableSet) *object.List {
+	result := &object.List{Data: []object.Object{}, IsSet: true}
+
+	for _, set := range sets {
+		set.Iterate(func(element object.Object) object.Object {
+			if (filter == nil || filter(element).Value) && !result.Contain(element).Value {
+				result.Data = append(result.Data, element)
+			}
+			return element
+		})
+	}
+	object.SortElements(result.Data)
+	return result
+}
+
+// mergeIntervals gives the union of two real intervals when they overlap or
+// touch, nil otherwise
+func mergeIntervals(left, right *object.RealInterval) *object.RealInterval {
+	if right.Lower.Less(left.Lower).Value {
+		left, right = right, left
+	}
+	if left.Upper.Less(right.Lower).Value ||
+		left.Upper.Equal(right.Lower).Value && left.UpperOpen && right.LowerOpen {
+		return nil
+	}
+
+	merged := *left
+	if left.Lower.Equal(right.Lower).Value {
+		merged.LowerOpen = left.LowerOpen && right.LowerOpen
+	}
+	if left.Upper.Less(right.Upper).Value {
+		merged.Upper, merged.UpperOpen = right.Upper, right.UpperOpen
+	} else if left.Upper.Equal(right.Upper).Value {
+		merged.UpperOpen = left.UpperOpen && right.UpperOpen
+	}
+	return &merged
+}
+
 func (ev *Evaluator) evalSetDiff(left, right object.Set) object.Set {
 	return &object.DiffSet{Left: left, Right: right}
 }
@@ -218,24 +354,46 @@ func (ev *Evaluator) evalSetExponent(left object.Set, right *object.Int) object.
 	return &object.ProductSet{Sets: sets}
 }
 
+// evalEquality decides '==' for every type, '!=' is always its negation. When
+// the left side can't compare with the right one the right side is asked, so
+// 1 == đúng is the same as đúng == 1
 func (ev *Evaluator) evalEquality(left, right object.Object) *object.Boolean {
-	errMsg := fmt.Sprintf("Không thể so sánh '%v' với '%v'", left.Type(), right.Type())
+	value := compareEqual(left, right)
+	if value == object.INCOMPARABLE {
+		value = compareEqual(right, left)
+	}
+	if value == object.INCOMPARABLE {
+		errMsg := errorhandler.CannotCompare.With(left.Type(), right.Type())
+		ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
+	}
+	return value
+}
 
-	if left, ok := left.(object.Equal); ok {
-		value := left.Equal(right)
-		value, isBool := ev.someObject(value, errMsg).(*object.Boolean)
-		if !isBool {
-			return FALSE
-		}
+func (ev *Evaluator) evalNotEqual(left, right object.Object) *object.Boolean {
+	value := ev.evalEquality(left, right)
+	if value == object.INCOMPARABLE {
 		return value
 	}
+	return value.Not()
+}
+
+func compareEqual(left, right object.Object) *object.Boolean {
+	if left, ok := left.(object.Equal); ok {
+		return left.Equal(right)
+	}
+	return object.INCOMPARABLE
+}
 
-	ev.runtimeError(errMsg)
-	return INCOMPARABLE
+func (ev *Evaluator) realEqualityHint(operator token.Token, left, right object.Object) {
+	_, leftReal := left.(*object.Real)
+	_, rightReal := right.(*object.Real)
+	if leftReal || rightReal {
+		ev.hint("real-equality", errorhandler.RealEqualityHint.With(string(operator.Literal)))
+	}
 }
 
 func (ev *Evaluator) evalLess(left, right object.Object) *object.Boolean {
-	errMsg := fmt.Sprintf("Không thể so sánh '%v' với '%v'", left.Type(), right.Type())
+	errMsg := errorhandler.CannotCompare.With(left.Type(), right.Type())
 
 	if left, ok := left.(object.StrictOrder); ok {
 		value := left.Less(right)
@@ -246,27 +404,64 @@ func (ev *Evaluator) evalLess(left, right object.Object) *object.Boolean {
 		return value
 	}
 
-	ev.runtimeError(errMsg)
+	ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
 	return INCOMPARABLE
 }
 
-func (ev *Evaluator) evalAnd(left, right object.Object) object.Object {
-	if !ev.isTruthy(left) {
+// evalLogical gives one of the operands of 'và' or 'hoặc' instead of a
+// boolean like in Python, so 'x hoặc 0' is x unless x is sai, 0 or rỗng. The
+// right side is only evaluated when the left one doesn't decide the result
+func (ev *Evaluator) evalLogical(node *ast.InfixExpression) object.Object {
+	left := ev.Eval(node.Left)
+	if _, isImply := left.(*object.Imply); isImply {
+		return left
+	}
+	if ev.isTruthy(left) == (node.Operator.Type == token.Or) {
 		return left
 	}
-	return right
+	return ev.Eval(node.Right)
 }
 
-func (ev *Evaluator) evalOr(left, right object.Object) object.Object {
-	if ev.isTruthy(left) {
-		return left
+// typeNames are the types 'là' can check, written in any letter case:
+// x là số nguyên
+var typeNames = []object.ObjectType{
+	object.IntObj, object.RealObj, object.QuotientObj, object.ComplexObj,
+	object.BoolObj, object.StringObj, object.ArrayObj, object.SetObj,
+	object.MapObj, object.FUNC_OBJ, object.NullObj,
+}
+
+// evalIs checks the type of the left side, the right side is the name of a
+// type instead of a value so it isn't evaluated
+func (ev *Evaluator) evalIs(node *ast.InfixExpression) object.Object {
+	name, ok := node.Right.(*ast.Identifier)
+	if !ok {
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errorhandler.IsWithoutType, node.Right)
 	}
-	return right
+
+	var expected object.ObjectType
+	for _, typeName := range typeNames {
+		if strings.EqualFold(string(typeName), name.Value) {
+			expected = typeName
+		}
+	}
+	if expected == "" {
+		names := []string{}
+		for _, typeName := range typeNames {
+			names = append(names, string(typeName))
+		}
+		errMsg := errorhandler.UnknownType.With(name.Value, strings.Join(names, ", "))
+		return ev.runtimeError(errorhandler.UNDEFINED_IDENT, errMsg, node.Right)
+	}
+
+	left := ev.Eval(node.Left)
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+	return boolRef(left.Type() == expected)
 }
 
 func (ev *Evaluator) evalBelong(left, right object.Object) *object.Boolean {
-	errMsg := fmt.Sprintf("Vế phải của mệnh đề 'thuộc' phải là một '%s' thay vì '%s'",
-		object.SetObj, right.Type())
+	errMsg := errorhandler.InvalidBelongSet.With(object.ObjectType(object.SetObj), right.Type())
 
 	if right, ok := right.(object.Set); ok {
 		value := right.Contain(left)
@@ -276,17 +471,20 @@ func (ev *Evaluator) evalBelong(left, right object.Object) *object.Boolean {
 		}
 		return value
 	}
+	if m, ok := right.(*object.Map); ok {
+		return m.Contain(left)
+	}
 
-	ev.runtimeError(errMsg)
+	ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
 	return INCOMPARABLE
 }
 
-func (ev *Evaluator) someObject(obj object.Object, errMsg string) object.Object {
+func (ev *Evaluator) someObject(obj object.Object, errMsg errorhandler.Message) object.Object {
 	if obj == object.INCOMPARABLE || obj == object.CANT_OPERATE {
-		return ev.runtimeError(errMsg)
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
 	}
 	if obj == object.ZERO_DIVISION {
-		return ev.runtimeError("Không thể chia cho 0")
+		return ev.runtimeError(errorhandler.ZERO_DIVISION, errorhandler.DivideByZero)
 	}
 
 	return obj
@@ -1,7 +1,9 @@
 package evaluator
 
 import (
+	"math/big"
 	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/object"
 	"vanvo/pkg/token"
 )
@@ -9,22 +11,61 @@ import (
 func (ev *Evaluator) evalForEachStatement(stmt *ast.ForEachStatement) object.Object {
 	closeEnv := object.NewEnclosedEnvironment(ev.Env)
 	callback := func(loopEnv *object.Environment) object.Object {
-		return ev.Eval(stmt.Body, loopEnv)
+		result := ev.Eval(stmt.Body, loopEnv)
+		if result.Type() == object.CONTINUE_OBJ && !escapesLoop(result, stmt.Label) {
+			return NULL
+		}
+		return result
+	}
+
+	result := ev.evalForEach(stmt.Conditions, []ast.Expression{}, nil, callback, closeEnv)
+	if result.Type() == object.BREAK_OBJ && !escapesLoop(result, stmt.Label) {
+		return NULL
+	}
+	return result
+}
+
+func isLoopControl(obj object.Object) bool {
+	return obj.Type() == object.BREAK_OBJ || obj.Type() == object.CONTINUE_OBJ
+}
+
+// escapesLoop tells if obj is a 'dừng' or 'tiếp' naming another loop than
+// the one with this label, so it has to leave this loop too
+func escapesLoop(obj object.Object, label *ast.Identifier) bool {
+	control, ok := obj.(*object.LoopControl)
+	if !ok || control.Label == "" {
+		return false
 	}
+	return label == nil || control.Label != label.Value
+}
 
-	return ev.evalForEach(stmt.Conditions, []ast.Expression{}, callback, closeEnv)
+func labelName(label *ast.Identifier) string {
+	if label == nil {
+		return ""
+	}
+	return label.Value
 }
 
+func (ev *Evaluator) loopControlOutsideLoop(obj object.Object) object.Object {
+	control := obj.(*object.LoopControl)
+	if control.Label != "" {
+		errMsg := errorhandler.UnknownLoopLabel.With(control.Label, control.Node.String())
+		return ev.runtimeError(errorhandler.MISPLACED, errMsg, control.Node)
+	}
+	errMsg := errorhandler.OnlyInLoop.With(control.Node.String())
+	return ev.runtimeError(errorhandler.MISPLACED, errMsg, control.Node)
+}
+
+// evalForEach runs callback for each binding given by the conditions, bound
+// holds the names an earlier 'thuộc' of the same header iterates over
 func (ev *Evaluator) evalForEach(
 	rawConditions []ast.Expression,
 	constraints []ast.Expression,
+	bound []string,
 	callback func(*object.Environment) object.Object,
 	env *object.Environment,
 ) object.Object {
 
-	var result object.Object
-	result = NULL
-
 	// if no condition left
 	if len(rawConditions) == 0 {
 		for _, cons := range constraints {
@@ -41,52 +82,224 @@ func (ev *Evaluator) evalForEach(
 		condition.Operator.Type == token.Belong {
 
 		if ident, isIdent := condition.Left.(*ast.Identifier); isIdent {
+			// evaluated once per pass in the scope of the outer variables, so
+			// 'y thuộc [1..x]' can depend on an earlier 'x thuộc A'
+			right := ev.Eval(condition.Right, env)
 
-			right := ev.Eval(condition.Right)
-			loopSet, isCountable := right.(object.CountableSet)
-			if !isCountable || !loopSet.IsCountable() {
-				errMsg := "Vế phải của mệnh đề 'thuộc' phải là một Tập đếm được"
-				return ev.runtimeError(errMsg, condition.Right)
+			if !isMembershipConstraint(ident, right, bound) {
+				return ev.iterateCondition(ident, condition, right, rawConditions, constraints, bound, callback, env)
 			}
+		}
+	}
 
-			loopSet.Iterate(func(element object.Object) object.Object {
-				if result.Type() == object.IMPLY_OBJ {
-					return result
-				}
-				env.SetInScope(ident.Value, element)
+	// constraints
+	constraints = append(constraints, rawConditions[0])
 
-				fullConditions := rawConditions
-				rawConditions = rawConditions[1:]
+	rawConditions = rawConditions[1:]
 
-				closeEnv := object.NewEnclosedEnvironment(env)
-				result = ev.evalForEach(rawConditions, constraints, callback, closeEnv)
+	closeEnv := object.NewEnclosedEnvironment(env)
+	return ev.evalForEach(rawConditions, constraints, bound, callback, closeEnv)
+}
 
-				rawConditions = fullConditions
+// iterateCondition binds ident to each element of the set in 'ident thuộc set'
+// and goes on with the rest of the conditions
+func (ev *Evaluator) iterateCondition(
+	ident *ast.Identifier,
+	condition *ast.InfixExpression,
+	right object.Object,
+	rawConditions []ast.Expression,
+	constraints []ast.Expression,
+	bound []string,
+	callback func(*object.Environment) object.Object,
+	env *object.Environment,
+) object.Object {
+
+	var result object.Object
+	result = NULL
+
+	// [1, 5] has the integers 1 to 5 as elements here, like [1..5]
+	if interval, ok := right.(*object.RealInterval); ok {
+		if integers, ok := interval.Integers(); ok {
+			right = integers
+		}
+	}
 
-				return result
-			})
+	var iterate func(object.IterateCallback)
+	if loopSet, ok := right.(object.CountableSet); ok && loopSet.IsCountable() {
+		iterate = loopSet.Iterate
+	} else if m, ok := right.(*object.Map); ok {
+		iterate = m.Iterate
+	} else {
+		errMsg := errorhandler.UncountableLoopSet
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, condition.Right)
+	}
+
+	// an infinite set like [1..] only stops when the body says so, a filter
+	// that never matches would run forever
+	infinite := false
+	if set, ok := right.(object.Set); ok {
+		infinite = object.IsInfinite(set)
+	}
+	iteration := 0
+	bound = append(bound[:len(bound):len(bound)], ident.Value)
 
+	iterate(func(element object.Object) object.Object {
+		if object.StopsIteration(result) {
 			return result
 		}
+		iteration++
+		if max := ev.Config.MaxIterations; infinite && max > 0 && iteration > max {
+			errMsg := errorhandler.TooManyIterations.With(max)
+			result = ev.runtimeError(errorhandler.ITERATION_LIMIT, errMsg, condition.Right)
+			return &object.LoopControl{Kind: object.BREAK_OBJ}
+		}
+		env.SetInScope(ident.Value, element)
+
+		closeEnv := object.NewEnclosedEnvironment(env)
+		result = ev.evalForEach(rawConditions[1:], constraints, bound, callback, closeEnv)
+
+		return result
+	})
+
+	return result
+}
+
+// isMembershipConstraint tells 'x thuộc [0, 1]' only checks an x bound by an
+// earlier 'thuộc' of the same header instead of iterating, since a continuous
+// interval has nothing to iterate. A name from outside the header doesn't
+// count, the interval is still an error there
+func isMembershipConstraint(ident *ast.Identifier, right object.Object, bound []string) bool {
+	set, ok := right.(object.Set)
+	if !ok || set.IsCountable() {
+		return false
+	}
+	for _, name := range bound {
+		if name == ident.Value {
+			return true
+		}
 	}
+	return false
+}
 
-	// constraints
-	constraints = append(constraints, rawConditions[0])
+func (ev *Evaluator) evalRepeatStatement(stmt *ast.RepeatStatement) object.Object {
+	if stmt.Counter != nil {
+		return ev.evalCounterLoop(stmt)
+	}
 
-	rawConditions = rawConditions[1:]
+	count := ev.Eval(stmt.Count)
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
 
-	closeEnv := object.NewEnclosedEnvironment(env)
-	return ev.evalForEach(rawConditions, constraints, callback, closeEnv)
+	n, ok := count.(*object.Int)
+	if !ok || n.Value.Sign() < 0 {
+		errMsg := errorhandler.InvalidRepeatCount.With(count.Display())
+		return ev.runtimeError(errorhandler.INVALID_VALUE, errMsg, stmt.Count)
+	}
+
+	for i := new(big.Int); i.Cmp(n.Value) < 0; i.Add(i, object.IntOne) {
+		result := ev.Eval(stmt.Body)
+		if ev.Errors.NotEmpty() || result.Type() == object.IMPLY_OBJ || escapesLoop(result, stmt.Label) {
+			return result
+		}
+		if result.Type() == object.BREAK_OBJ {
+			break
+		}
+	}
+	return NULL
+}
+
+// evalCounterLoop runs 'lặp i từ a đến b bước s', both ends are included and
+// the step is 1 or -1 depending on the direction when it's not given
+func (ev *Evaluator) evalCounterLoop(stmt *ast.RepeatStatement) object.Object {
+	from, ok := ev.Eval(stmt.From).(object.Realness)
+	if !ok {
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errorhandler.InvalidLoopStart, stmt.From)
+	}
+	to, ok := ev.Eval(stmt.To).(object.Realness)
+	if !ok {
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errorhandler.InvalidLoopEnd, stmt.To)
+	}
+
+	var step object.Realness = object.NewInt(big.NewInt(1))
+	if to.Less(from).Value {
+		step = object.NewInt(big.NewInt(-1))
+	}
+	if stmt.Step != nil {
+		step, ok = ev.Eval(stmt.Step).(object.Realness)
+		if !ok {
+			return ev.runtimeError(errorhandler.INVALID_TYPE, errorhandler.InvalidLoopStep, stmt.Step)
+		}
+		if step.ToReal().IsZero() {
+			return ev.runtimeError(errorhandler.INVALID_VALUE, errorhandler.ZeroLoopStep, stmt.Step)
+		}
+	}
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+	descending := step.ToReal().Value.Sign() < 0
+
+	for i := from; ; i = i.Add(step).(object.Realness) {
+		if !descending && to.Less(i).Value || descending && i.Less(to).Value {
+			break
+		}
+
+		loopEnv := object.NewEnclosedEnvironment(ev.Env)
+		loopEnv.SetInScope(stmt.Counter.Value, i)
+
+		result := ev.Eval(stmt.Body, loopEnv)
+		if ev.Errors.NotEmpty() || result.Type() == object.IMPLY_OBJ || escapesLoop(result, stmt.Label) {
+			return result
+		}
+		if result.Type() == object.BREAK_OBJ {
+			break
+		}
+	}
+	return NULL
 }
 
 func (ev *Evaluator) evalForStatement(stmt *ast.ForStatement) object.Object {
-	for {
+	for iteration := 1; ; iteration++ {
 		for _, cond := range stmt.Conditions {
 			check := ev.Eval(cond)
 			if !ev.isTruthy(check) {
 				return NULL
 			}
 		}
-		ev.Eval(stmt.Body)
+		if max := ev.Config.MaxIterations; max > 0 && iteration > max {
+			errMsg := errorhandler.TooManyIterations.With(max)
+			return ev.runtimeError(errorhandler.ITERATION_LIMIT, errMsg)
+		}
+		result := ev.Eval(stmt.Body)
+		if result.Type() == object.IMPLY_OBJ || escapesLoop(result, stmt.Label) {
+			return result
+		}
+		if result.Type() == object.BREAK_OBJ {
+			return NULL
+		}
+	}
+}
+
+// evalWhileStatement runs the body while the condition is truthy, each pass
+// has its own scope so 'cho' in the body declares a fresh name every time
+func (ev *Evaluator) evalWhileStatement(stmt *ast.WhileStatement) object.Object {
+	for iteration := 1; ; iteration++ {
+		check := ev.Eval(stmt.Condition)
+		if ev.Errors.NotEmpty() || !ev.isTruthy(check) {
+			return NULL
+		}
+		if max := ev.Config.MaxIterations; max > 0 && iteration > max {
+			errMsg := errorhandler.TooManyIterations.With(max)
+			return ev.runtimeError(errorhandler.ITERATION_LIMIT, errMsg, stmt.Condition)
+		}
+
+		loopEnv := object.NewEnclosedEnvironment(ev.Env)
+		result := ev.Eval(stmt.Body, loopEnv)
+		if ev.Errors.NotEmpty() || result.Type() == object.IMPLY_OBJ || escapesLoop(result, stmt.Label) {
+			return result
+		}
+		if result.Type() == object.BREAK_OBJ {
+			return NULL
+		}
 	}
 }
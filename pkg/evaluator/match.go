@@ -0,0 +1,47 @@
+package evaluator
+
+import (
+	"vanvo/pkg/ast"
+	"vanvo/pkg/object"
+)
+
+// evalMatchStatement runs the first branch whose pattern matches the value,
+// branches are tried in order so a narrower interval should come first
+func (ev *Evaluator) evalMatchStatement(ms *ast.MatchStatement) object.Object {
+	value := ev.Eval(ms.Value)
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+
+	for _, branch := range ms.Branches {
+		if branch.Pattern == nil {
+			return ev.Eval(branch.Body)
+		}
+
+		pattern := ev.Eval(branch.Pattern)
+		if ev.matchPattern(value, pattern) {
+			return ev.Eval(branch.Body)
+		}
+		if ev.Errors.NotEmpty() {
+			return NULL
+		}
+	}
+
+	return NULL
+}
+
+// matchPattern checks the value belongs to an interval pattern like [0, 10],
+// other patterns have to be equal to it. A value that can't be compared with
+// the pattern like "a" with 1 just doesn't match
+func (ev *Evaluator) matchPattern(value, pattern object.Object) bool {
+	switch pattern := pattern.(type) {
+	case *object.RealInterval, *object.IntInterval:
+		return pattern.(object.Set).Contain(value) == TRUE
+	default:
+		matched := compareEqual(value, pattern)
+		if matched == object.INCOMPARABLE {
+			matched = compareEqual(pattern, value)
+		}
+		return matched == TRUE
+	}
+}
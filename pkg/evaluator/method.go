@@ -0,0 +1,54 @@
+package evaluator
+
+import (
+	"vanvo/pkg/ast"
+	"vanvo/pkg/object"
+	"vanvo/pkg/token"
+)
+
+// methods maps the method names of each type to the builtin they call with
+// the receiver as the first argument: [3, 1, 2].sắp_xếp() is sắp_xếp([3, 1, 2])
+var methods = map[object.ObjectType]map[string]string{
+	object.ArrayObj: {
+		"sắp_xếp":  "sắp_xếp",
+		"độ_dài":   "len",
+		"sao_chép": "sao chép",
+	},
+	object.SetObj: {
+		"sắp_xếp":  "sắp_xếp",
+		"độ_dài":   "len",
+		"số_lượng": "sốLượng",
+		"sao_chép": "sao chép",
+	},
+	object.StringObj: {
+		"độ_dài":        "len",
+		"mã":            "mã",
+		"thay_thế":      "thay_thế",
+		"bắt_đầu_bằng":  "bắt_đầu_bằng",
+		"kết_thúc_bằng": "kết_thúc_bằng",
+	},
+}
+
+// evalMethodCall handles calls like xs.sắp_xếp(), ok is false when the call
+// isn't a method call so it's evaluated as a normal one
+func (ev *Evaluator) evalMethodCall(call *ast.CallExpression) (result object.Object, ok bool) {
+	member, isInfix := call.Function.(*ast.InfixExpression)
+	if !isInfix || member.Operator.Type != token.Dot {
+		return nil, false
+	}
+	name, isIdent := member.Right.(*ast.Identifier)
+	if !isIdent {
+		return nil, false
+	}
+
+	receiver := ev.Eval(member.Left)
+	builtin, found := methods[receiver.Type()][name.Value]
+	if !found {
+		fn := ev.evalInfixExpression(member.Operator, receiver, ev.Eval(member.Right))
+		return ev.applyCall(call, fn), true
+	}
+
+	fn := object.Builtins[builtin].(*object.Function)
+	args := append([]object.Object{receiver}, ev.evalExpressions(call.Arguments)...)
+	return ev.callFunction(fn, args...), true
+}
@@ -1,8 +1,8 @@
 package evaluator
 
 import (
-	"fmt"
 	"math/big"
+	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/object"
 	"vanvo/pkg/token"
 )
@@ -26,6 +26,36 @@ func (ev *Evaluator) evalPrefixExpression(operator token.Token, right object.Obj
 	}
 }
 
+func (ev *Evaluator) evalPostfixExpression(operator token.Token, left object.Object) object.Object {
+	if left, isImply := left.(*object.Imply); isImply {
+		return left
+	}
+
+	switch operator.Type {
+	case token.Bang:
+		return ev.evalFactorial(left)
+	default:
+		return NULL
+	}
+}
+
+// MaxFactorial is the largest n that n! is computed for, 100000! already has
+// 456574 digits
+const MaxFactorial = 100_000
+
+func (ev *Evaluator) evalFactorial(left object.Object) object.Object {
+	n, ok := left.(*object.Int)
+	if !ok || n.Value.Sign() < 0 {
+		errMsg := errorhandler.InvalidFactorial.With(left.Display())
+		return ev.runtimeError(errorhandler.INVALID_VALUE, errMsg)
+	}
+	if n.Value.Cmp(big.NewInt(MaxFactorial)) > 0 {
+		errMsg := errorhandler.FactorialTooLarge.With(left.Display(), MaxFactorial)
+		return ev.runtimeError(errorhandler.INVALID_VALUE, errMsg)
+	}
+	return object.NewInt(new(big.Int).MulRange(1, n.Value.Int64()))
+}
+
 func (ev *Evaluator) evalBangPrefix(right object.Object) object.Object {
 	switch right {
 	case TRUE:
@@ -40,12 +70,18 @@ func (ev *Evaluator) evalBangPrefix(right object.Object) object.Object {
 }
 
 func (ev *Evaluator) evalHashPrefix(right object.Object) object.Object {
-	if set, ok := right.(object.CountableSet); ok && set.IsCountable() {
-		val := big.NewInt(int64(set.Length()))
+	if set, ok := right.(object.Set); ok {
+		return ev.builtinResult(object.Cardinality(set))
+	}
+	if m, ok := right.(*object.Map); ok {
+		return object.NewInt(big.NewInt(int64(m.Length())))
+	}
+	if str, ok := right.(*object.String); ok {
+		val := big.NewInt(int64(str.Length()))
 		return object.NewInt(val)
 	}
-	errMsg := fmt.Sprintf("Không thể lấy độ dài của '%v'", right.Type())
-	return ev.runtimeError(errMsg)
+	errMsg := errorhandler.CannotTakeLength.With(right.Type())
+	return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
 }
 
 func (ev *Evaluator) evalMinusPrefix(right object.Object) object.Object {
@@ -0,0 +1,173 @@
+package evaluator
+
+import (
+	"math/big"
+	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
+	"vanvo/pkg/object"
+	"vanvo/pkg/token"
+)
+
+// evalSpecialForm handles the calls whose arguments can't all be evaluated
+// first, like the index and the body of tổng(i, 1, n, i^2), tích(i, 1, n, i)
+// or tổng(x thuộc A, x^2). A function declared with the same name is called as
+// usual instead
+func (ev *Evaluator) evalSpecialForm(call *ast.CallExpression) (result object.Object, ok bool) {
+	ident, isIdent := call.Function.(*ast.Identifier)
+	if !isIdent {
+		return nil, false
+	}
+	if _, defined := ev.Env.Get(ident.Value); defined {
+		return nil, false
+	}
+
+	switch ident.Value {
+	case "tổng":
+		return ev.evalSeries(call, object.NewInt(big.NewInt(0)), ev.evalAddition), true
+	case "tích":
+		return ev.evalSeries(call, object.NewInt(big.NewInt(1)), ev.evalMultiplication), true
+	}
+	return nil, false
+}
+
+// evalSeries evaluates the body for each index from the lower bound to the
+// upper bound and folds the values with combine, an empty range gives identity
+func (ev *Evaluator) evalSeries(
+	call *ast.CallExpression,
+	identity object.Object,
+	combine func(left, right object.Object) object.Object,
+) object.Object {
+
+	name := call.Function.String()
+	if len(call.Arguments) >= 2 && isBelongClause(call.Arguments[0]) {
+		return ev.evalGeneratorSeries(call, identity, combine)
+	}
+	if len(call.Arguments) != 4 {
+		errMsg := errorhandler.SeriesArgumentCount.With(len(call.Arguments), name)
+		return ev.runtimeError(errorhandler.ARGUMENT_COUNT, errMsg)
+	}
+	index, ok := call.Arguments[0].(*ast.Identifier)
+	if !ok {
+		errMsg := errorhandler.SeriesIndex.With(name)
+		return ev.runtimeError(errorhandler.INVALID_VALUE, errMsg, call.Arguments[0])
+	}
+
+	from, ok := ev.Eval(call.Arguments[1]).(object.Realness)
+	if !ok {
+		errMsg := errorhandler.SeriesLower.With(name)
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, call.Arguments[1])
+	}
+	to, ok := ev.Eval(call.Arguments[2]).(object.Realness)
+	if !ok {
+		errMsg := errorhandler.SeriesUpper.With(name)
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, call.Arguments[2])
+	}
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+
+	// the number of terms is known before adding any of them
+	if max := ev.Config.MaxIterations; max > 0 {
+		limit := object.NewInt(big.NewInt(int64(max)))
+		if !to.Subtract(from).(object.Realness).Less(limit).Value {
+			errMsg := errorhandler.TooManyTerms.With(name, max)
+			return ev.runtimeError(errorhandler.ITERATION_LIMIT, errMsg, call.Arguments[2])
+		}
+	}
+
+	body := call.Arguments[3]
+	result := identity
+	step := object.NewInt(big.NewInt(1))
+
+	for i := from; !to.Less(i).Value; i = i.Add(step).(object.Realness) {
+		env := object.NewEnclosedEnvironment(ev.Env)
+		env.SetInScope(index.Value, i)
+
+		result = ev.foldTerm(name, body, env, result, combine)
+		if ev.Errors.NotEmpty() {
+			return NULL
+		}
+	}
+	return result
+}
+
+// evalGeneratorSeries folds the last argument over the elements given by the
+// other ones, which are read like the conditions of 'với mỗi':
+// tổng(x thuộc A, x > 2, x^2)
+func (ev *Evaluator) evalGeneratorSeries(
+	call *ast.CallExpression,
+	identity object.Object,
+	combine func(left, right object.Object) object.Object,
+) object.Object {
+
+	name := call.Function.String()
+	conditions := call.Arguments[:len(call.Arguments)-1]
+	body := call.Arguments[len(call.Arguments)-1]
+	result := identity
+	terms := 0
+
+	callback := func(env *object.Environment) object.Object {
+		terms++
+		if max := ev.Config.MaxIterations; max > 0 && terms > max {
+			errMsg := errorhandler.TooManyTerms.With(name, max)
+			ev.runtimeError(errorhandler.ITERATION_LIMIT, errMsg, call.Arguments[0])
+			return &object.LoopControl{Kind: object.BREAK_OBJ}
+		}
+		result = ev.foldTerm(name, body, env, result, combine)
+		if ev.Errors.NotEmpty() {
+			return &object.LoopControl{Kind: object.BREAK_OBJ}
+		}
+		return NULL
+	}
+
+	// the first set is read here to refuse an infinite one, every element of
+	// it is a term and a filter can't make it end
+	env := object.NewEnclosedEnvironment(ev.Env)
+	generator := conditions[0].(*ast.InfixExpression)
+	ident := generator.Left.(*ast.Identifier)
+	right := ev.Eval(generator.Right, env)
+	if set, ok := right.(object.Set); ok && set.IsCountable() && object.IsInfinite(set) {
+		errMsg := errorhandler.InfiniteSeries.With(name)
+		return ev.runtimeError(errorhandler.ITERATION_LIMIT, errMsg, generator.Right)
+	}
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+	ev.iterateCondition(ident, generator, right, conditions, []ast.Expression{}, nil, callback, env)
+
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+	return result
+}
+
+// foldTerm evaluates the body in env and combines it with the result so far,
+// the body has to be a number
+func (ev *Evaluator) foldTerm(
+	name string,
+	body ast.Expression,
+	env *object.Environment,
+	result object.Object,
+	combine func(left, right object.Object) object.Object,
+) object.Object {
+
+	value := ev.Eval(body, env)
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+	if _, isNumber := value.(object.Number); !isNumber {
+		errMsg := errorhandler.SeriesTerm.With(name, value.Type())
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, body)
+	}
+	return combine(result, value)
+}
+
+// isBelongClause tells if exp binds a variable like 'x thuộc A'
+func isBelongClause(exp ast.Expression) bool {
+	infix, ok := exp.(*ast.InfixExpression)
+	if !ok || infix.Operator.Type != token.Belong {
+		return false
+	}
+	_, isIdent := infix.Left.(*ast.Identifier)
+	return isIdent
+}
@@ -1,9 +1,10 @@
 package evaluator
 
 import (
-	"fmt"
 	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/object"
+	"vanvo/pkg/token"
 )
 
 func (ev *Evaluator) evalIndex(exp *ast.IndexExpression) object.Object {
@@ -11,30 +12,67 @@ func (ev *Evaluator) evalIndex(exp *ast.IndexExpression) object.Object {
 	index := ev.Eval(exp.Index)
 
 	if set, ok := set.(object.Set); ok && !set.IsCountable() {
-		return ev.runtimeError("Không thể dùng tập không đếm được để truy cập chỉ số")
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errorhandler.IndexUncountable)
 	}
 
 	if set, ok := set.(object.Indexable); ok {
 		return ev.indexing(set, index)
 	}
+	if set, ok := set.(*object.Map); ok {
+		return ev.mapLookup(set, index)
+	}
+
+	errMsg := errorhandler.CannotIndex.With(set.Type())
+	return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
+}
 
-	errMsg := fmt.Sprintf("Không thể truy cập chỉ số vào '%v'", set.Type())
-	return ev.runtimeError(errMsg)
+func (ev *Evaluator) mapLookup(m *object.Map, key object.Object) object.Object {
+	if key, ok := object.AsHashable(key); ok {
+		if val, ok := m.Get(key); ok {
+			return val
+		}
+	}
+	errMsg := errorhandler.MissingMapKey.With(key.Display())
+	return ev.runtimeError(errorhandler.INDEX_OUT_OF_RANGE, errMsg)
 }
 
 func (ev *Evaluator) indexing(set object.Indexable, index object.Object) object.Object {
+	return ev.atIndex(set, index, set.At)
+}
+
+// atIndex gives the element at index to access, which reads or writes it.
+// Negative indices count from the end like xs[-1] for the last element
+func (ev *Evaluator) atIndex(
+	set object.Object,
+	index object.Object,
+	access func(int) object.Object,
+) object.Object {
+
 	if index, ok := index.(*object.Int); ok {
-		val := set.At(int(index.Value.Int64()))
+		sized, hasLength := set.(interface{ Length() int })
+		if countable, ok := set.(object.Set); ok && object.IsInfinite(countable) {
+			hasLength = false
+		}
+
+		i := int(index.Value.Int64())
+		if i < 0 && hasLength {
+			i += sized.Length()
+		}
+		val := access(i)
 
 		if val == object.IndexError {
-			errMsg := fmt.Sprintf("Chỉ số vượt quá độ dài của '%v'", set.Type())
-			return ev.runtimeError(errMsg)
+			if !hasLength {
+				errMsg := errorhandler.InvalidInfiniteIndex.With(index.Display())
+				return ev.runtimeError(errorhandler.INDEX_OUT_OF_RANGE, errMsg)
+			}
+			errMsg := errorhandler.IndexOutOfRange.With(index.Display(), sized.Length(), set.Type())
+			return ev.runtimeError(errorhandler.INDEX_OUT_OF_RANGE, errMsg)
 		}
 		return val
 	}
 
-	errMsg := fmt.Sprintf("Chỉ số phải là một '%v' thay vì '%v'", object.IntObj, index.Type())
-	return ev.runtimeError(errMsg)
+	errMsg := errorhandler.InvalidIndexType.With(object.ObjectType(object.IntObj), index.Type())
+	return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg)
 }
 
 func (ev *Evaluator) evalList(list *ast.List) object.Object {
@@ -42,6 +80,14 @@ func (ev *Evaluator) evalList(list *ast.List) object.Object {
 	return &object.List{Data: exps}
 }
 
+func (ev *Evaluator) evalArray(arr *ast.Array) object.Object {
+	exps := ev.evalExpressions(arr.Data)
+	if exps == nil {
+		exps = []object.Object{}
+	}
+	return &object.Array{Data: exps}
+}
+
 func (ev *Evaluator) evalListComprehension(node *ast.ListComprehension) object.Object {
 	closeEnv := object.NewEnclosedEnvironment(ev.Env)
 
@@ -59,12 +105,82 @@ func (ev *Evaluator) evalListComprehension(node *ast.ListComprehension) object.O
 			list.Channel <- val
 			return val
 		}
-		ev.evalForEach(node.Conditions, []ast.Expression{}, callback, closeEnv)
+		ev.evalForEach(node.Conditions, []ast.Expression{}, nil, callback, closeEnv)
 	}()
 
 	return list
 }
 
+func (ev *Evaluator) evalSetComprehension(node *ast.SetComprehension) object.Object {
+	closeEnv := object.NewEnclosedEnvironment(ev.Env)
+	set := &object.List{Data: []object.Object{}, IsSet: true}
+
+	callback := func(env *object.Environment) object.Object {
+		val := ev.Eval(node.Expression, env)
+		if !set.Contain(val).Value {
+			set.Data = append(set.Data, val)
+		}
+		return val
+	}
+	ev.evalForEach(node.Conditions, []ast.Expression{}, nil, callback, closeEnv)
+
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+	object.SortElements(set.Data)
+	return set
+}
+
+func (ev *Evaluator) evalMapComprehension(node *ast.MapComprehension) object.Object {
+	closeEnv := object.NewEnclosedEnvironment(ev.Env)
+	result := object.NewMap()
+
+	callback := func(env *object.Environment) object.Object {
+		keyObj := ev.Eval(node.Key, env)
+		val := ev.Eval(node.Value, env)
+		if ev.Errors.NotEmpty() {
+			return NULL
+		}
+
+		key, ok := object.AsHashable(keyObj)
+		if !ok {
+			errMsg := errorhandler.InvalidMapKey.With(keyObj.Type())
+			return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, node.Key)
+		}
+		return result.Set(key, val)
+	}
+	ev.evalForEach(node.Conditions, []ast.Expression{}, nil, callback, closeEnv)
+
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+	return result
+}
+
+// evalQuantifier checks the predicate for each element given by the
+// conditions, 'với mọi' stops at the first counterexample and 'tồn tại' at
+// the first witness
+func (ev *Evaluator) evalQuantifier(node *ast.QuantifierExpression) object.Object {
+	closeEnv := object.NewEnclosedEnvironment(ev.Env)
+	stopAt := node.Token.Type == token.Exists
+	result := !stopAt
+
+	callback := func(env *object.Environment) object.Object {
+		value := ev.Eval(node.Predicate, env)
+		if ev.Errors.NotEmpty() || ev.isTruthy(value) == stopAt {
+			result = stopAt
+			return &object.LoopControl{Kind: object.BREAK_OBJ}
+		}
+		return NULL
+	}
+	ev.evalForEach(node.Conditions, []ast.Expression{}, nil, callback, closeEnv)
+
+	if ev.Errors.NotEmpty() {
+		return NULL
+	}
+	return boolRef(result)
+}
+
 func (ev *Evaluator) evalIntInterval(interval *ast.IntInterval) object.Object {
 	lowerObj := ev.Eval(interval.Lower)
 	upperObj := ev.Eval(interval.Upper)
@@ -72,25 +188,37 @@ func (ev *Evaluator) evalIntInterval(interval *ast.IntInterval) object.Object {
 	lower, ok1 := lowerObj.(object.Realness)
 	upper, ok2 := upperObj.(object.Realness)
 	if !ok1 {
-		errMsg := fmt.Sprintf("Không thể dùng '%s' làm chặn dưới", lowerObj.Type())
-		return ev.runtimeError(errMsg, interval.Lower)
+		errMsg := errorhandler.InvalidLowerBound.With(lowerObj.Type())
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, interval.Lower)
 	}
 	if !ok2 {
-		errMsg := fmt.Sprintf("Không thể dùng '%s' làm chặn trên", upperObj.Type())
-		return ev.runtimeError(errMsg, interval.Upper)
+		errMsg := errorhandler.InvalidUpperBound.With(upperObj.Type())
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, interval.Upper)
 	}
 
 	var step object.Realness = object.NewInt(object.IntOne)
 
 	if interval.Step != nil {
-		step, ok1 = ev.Eval(interval.Step).(object.Realness)
+		stepObj := ev.Eval(interval.Step)
+		step, ok1 = stepObj.(object.Realness)
 		if !ok1 {
-			errMsg := fmt.Sprintf("Không thể dùng '%s' làm bước nhảy", step.Type())
-			return ev.runtimeError(errMsg, interval.Upper)
+			errMsg := errorhandler.InvalidIntervalStep.With(stepObj.Type())
+			return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, interval.Step)
+		}
+		if step.ToReal().IsZero() {
+			return ev.runtimeError(errorhandler.INVALID_VALUE, errorhandler.ZeroIntervalStep, interval.Step)
 		}
 	}
 
-	return &object.IntInterval{Lower: lower, Upper: upper, Step: step}
+	// open bounds are dropped by moving them one step inside: (1..5) is [2..4]
+	result := &object.IntInterval{Lower: lower, Upper: upper, Step: step}
+	if interval.UpperOpen && result.Contain(upper).Value {
+		result.Upper = upper.Subtract(step).(object.Realness)
+	}
+	if interval.LowerOpen {
+		result.Lower = lower.Add(step).(object.Realness)
+	}
+	return result
 }
 
 func (ev *Evaluator) evalRealInterval(interval *ast.RealInterval) object.Object {
@@ -100,13 +228,18 @@ func (ev *Evaluator) evalRealInterval(interval *ast.RealInterval) object.Object
 	lower, ok1 := lowerObj.(object.Realness)
 	upper, ok2 := upperObj.(object.Realness)
 	if !ok1 {
-		errMsg := fmt.Sprintf("Không thể dùng '%s' làm chặn dưới", lowerObj.Type())
-		return ev.runtimeError(errMsg)
+		errMsg := errorhandler.InvalidLowerBound.With(lowerObj.Type())
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, interval.Lower)
 	}
 	if !ok2 {
-		errMsg := fmt.Sprintf("Không thể dùng '%s' làm chặn trên", lowerObj.Type())
-		return ev.runtimeError(errMsg)
+		errMsg := errorhandler.InvalidUpperBound.With(upperObj.Type())
+		return ev.runtimeError(errorhandler.INVALID_TYPE, errMsg, interval.Upper)
 	}
 
-	return &object.RealInterval{Lower: lower, Upper: upper}
+	return &object.RealInterval{
+		Lower:     lower,
+		Upper:     upper,
+		LowerOpen: interval.LowerOpen,
+		UpperOpen: interval.UpperOpen,
+	}
 }
@@ -0,0 +1,71 @@
+package evaluator
+
+import (
+	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
+	"vanvo/pkg/object"
+	"vanvo/pkg/token"
+)
+
+// validate reports the mistakes that can be seen without running the program.
+// A constant expression like 1/0 or (-8)^(1/3) is evaluated with the usual
+// runtime checks, so it fails here the same way it would later. A constant
+// interval like [5, 1] isn't an error but it's always empty, so it's warned
+func (ev *Evaluator) validate(program *ast.Program) {
+	ast.Inspect(program, func(node ast.Node) bool {
+		if ev.Errors.NotEmpty() {
+			return false
+		}
+
+		switch node := node.(type) {
+		case *ast.InfixExpression:
+			if isConstant(node) {
+				ev.Eval(node)
+				return false
+			}
+		case *ast.IntInterval:
+			if node.Step == nil {
+				ev.checkIntervalBounds(node, node.Lower, node.Upper)
+			}
+		case *ast.RealInterval:
+			ev.checkIntervalBounds(node, node.Lower, node.Upper)
+		}
+		return true
+	})
+}
+
+func (ev *Evaluator) checkIntervalBounds(interval ast.Node, lower, upper ast.Expression) {
+	if !isConstant(lower) || !isConstant(upper) {
+		return
+	}
+	lowerObj, ok1 := ev.Eval(lower).(object.Realness)
+	upperObj, ok2 := ev.Eval(upper).(object.Realness)
+
+	if ok1 && ok2 && upperObj.Less(lowerObj).Value {
+		message := errorhandler.EmptyInterval.With(interval.String())
+		ev.Errors.AddWarning(message, interval)
+	}
+}
+
+// isConstant tells if an expression is made only of literals, so evaluating
+// it early has no effect on the program
+func isConstant(exp ast.Node) bool {
+	switch exp := exp.(type) {
+	case *ast.Int, *ast.Real, *ast.Imaginary, *ast.Boolean, *ast.String:
+		return true
+	case *ast.PrefixExpression:
+		return exp.Operator.Type == token.Minus && isConstant(exp.Right)
+	case *ast.InfixExpression:
+		return isConstant(exp.Left) && isConstant(exp.Right)
+	case *ast.ExpressionStatement:
+		// a group of one expression is parsed as that statement
+		return isConstant(exp.Expression)
+	case *ast.GroupExpression:
+		if len(exp.Statements) != 1 {
+			return false
+		}
+		stmt, ok := exp.Statements[0].(*ast.ExpressionStatement)
+		return ok && isConstant(stmt.Expression)
+	}
+	return false
+}
@@ -6,11 +6,18 @@ import (
 
 var VNALPHA = arrToMap([]rune("aAàÀảẢãÃáÁạẠăĂằẰẳẲẵẴắẮặẶâÂầẦẩẨẫẪấẤậẬbBcCdDđĐeEèÈẻẺẽẼéÉẹẸêÊềỀểỂễỄếẾệỆfFgGhHiIìÌỉỈĩĨíÍịỊjJkKlLmMnNoOòÒỏỎõÕóÓọỌôÔồỒổỔỗỖốỐộỘơƠờỜởỞỡỠớỚợỢpPqQrRsStTuUùÙủỦũŨúÚụỤưƯừỪửỬữỮứỨựỰvVwWxXyYỳỲỷỶỹỸýÝỵỴzZ"))
 
+var ESCAPE_CHARS = map[rune]rune{
+	'n':  '\n',
+	't':  '\t',
+	'\\': '\\',
+	'"':  '"',
+}
+
 func mergeToken(tok1 token.Token, tok2 token.Token) token.Token {
 	if len(tok2.Literal) == 0 {
 		return tok1
 	}
-	tok := token.Token{}
+	tok := token.Token{Line: tok1.Line, Column: tok1.Column}
 	tok.Literal = tok1.Literal
 	tok.Literal = append(tok.Literal, ' ')
 	tok.Literal = append(tok.Literal, tok2.Literal...)
@@ -19,6 +26,26 @@ func mergeToken(tok1 token.Token, tok2 token.Token) token.Token {
 	return tok
 }
 
+// endsOperand tells if a token of this type can be the end of an operand
+func endsOperand(tokenType token.TokenType) bool {
+	switch tokenType {
+	case token.Ident, token.Int, token.Real, token.Imag, token.String, token.True, token.False,
+		token.RParen, token.RBracket, token.RBrace:
+		return true
+	}
+	return false
+}
+
+// startsStatement tells if the token after one of this type is the first word
+// of a statement, the empty type is the start of the input
+func startsStatement(tokenType token.TokenType) bool {
+	switch tokenType {
+	case "", token.Endline, token.Colon, token.Semicolon:
+		return true
+	}
+	return false
+}
+
 func arrToMap(arr []rune) map[rune]bool {
 	m := make(map[rune]bool)
 	for _, each := range arr {
@@ -41,3 +68,12 @@ func isLetter(ch rune) bool {
 func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
+
+func isBasePrefix(ch rune) bool {
+	_, ok := token.INT_BASES[ch]
+	return ok
+}
+
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
@@ -1,6 +1,9 @@
 package lexer
 
 import (
+	"strconv"
+	"unicode"
+	"unicode/utf8"
 	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/token"
 )
@@ -14,6 +17,8 @@ type Lexer struct {
 	ch              rune
 	isPreviousIdent bool
 	tempNextToken   token.Token
+	previousType    token.TokenType
+	inRepeatHeader  bool
 	line            int
 	column          int
 }
@@ -34,6 +39,19 @@ func New(input string, errors *errorhandler.ErrorList) *Lexer {
 }
 
 func (l *Lexer) AdvanceToken() token.Token {
+	tok := l.readToken()
+	l.previousType = tok.Type
+
+	switch tok.Type {
+	case token.Repeat:
+		l.inRepeatHeader = true
+	case token.Colon, token.Endline, token.EOF:
+		l.inRepeatHeader = false
+	}
+	return tok
+}
+
+func (l *Lexer) readToken() token.Token {
 	if l.tempNextToken.Type != "" {
 		nextToken := l.tempNextToken
 		l.tempNextToken = token.Token{}
@@ -49,23 +67,36 @@ func (l *Lexer) AdvanceToken() token.Token {
 			l.skipComment()
 			return l.AdvanceToken()
 		}
+		if tok.Type == token.LParenAsterisk || tok.Type == token.SlashAsterisk {
+			l.skipBlockComment(tok)
+			return l.AdvanceToken()
+		}
 		l.readChar()
 		return tok
 	}
 
 	switch l.ch {
 	case '"':
+		column := l.column
 		tok = l.newToken(token.String, l.consumeString())
+		tok.Column = column + 1
 	case '\n':
+		// the literal is the leading whitespace of the next line, a tab
+		// counts as 4 spaces, the parser reads the indentation from it. The
+		// position is the end of the line so errors like a missing ')' point
+		// right after the last character
+		tok = l.newSingleToken(token.Endline)
 		l.line += 1
 		l.column = 0
-		tok = l.newSingleToken(token.Endline)
 		tok.Literal = l.consumeSpace()
-		tok.Column = 1
 	case 0:
 		tok = l.newToken(token.EOF, []rune{})
 	default:
-		if isDigit(l.ch) {
+		if l.ch == '0' && isBasePrefix(l.peekChar()) && isLetter(l.peekPeekChar()) {
+			// 0x1F, 0b1010, 0o17, digits are checked by the parser
+			return l.newToken(token.Int, l.consumeBasedNumber())
+
+		} else if isDigit(l.ch) {
 			tokenType := token.TokenType(token.Int)
 			literal := l.consumeNumber()
 
@@ -74,6 +105,24 @@ func (l *Lexer) AdvanceToken() token.Token {
 				literal = append(literal, l.ch)
 				l.readChar()
 				literal = append(literal, l.consumeNumber()...)
+
+				// a second point like 1.2.3 stays in the literal, the parser
+				// reports it as one invalid number
+				for l.ch == '.' && isDigit(l.peekChar()) {
+					literal = append(literal, l.ch)
+					l.readChar()
+					literal = append(literal, l.consumeNumber()...)
+				}
+			}
+			if exponent := l.consumeExponent(); exponent != nil {
+				tokenType = token.Real
+				literal = append(literal, exponent...)
+			}
+			// 3i is imaginary but 3in stays 3 * in
+			if l.ch == 'i' && !isLetter(l.peekChar()) {
+				literal = append(literal, l.ch)
+				l.readChar()
+				return l.newToken(token.Imag, literal)
 			}
 			tok = l.newToken(tokenType, literal)
 			return tok
@@ -81,6 +130,9 @@ func (l *Lexer) AdvanceToken() token.Token {
 		} else if isLetter(l.ch) {
 			tokenLiteral := l.consumeIdent()
 			tokenType := token.LookupKeyword(tokenLiteral)
+			if tokenType == token.Ident {
+				tokenType = l.lookupSoftKeyword(tokenLiteral)
+			}
 			tok := l.newToken(tokenType, tokenLiteral)
 
 			pos := l.position
@@ -90,6 +142,10 @@ func (l *Lexer) AdvanceToken() token.Token {
 			nextToken := l.newToken(token.Ident, l.consumeIdent())
 
 			doubleToken := mergeToken(tok, nextToken)
+			if doubleToken.Type == token.Ident && len(nextToken.Literal) > 0 {
+				// two words like 'trong khi' can be a soft keyword
+				doubleToken.Type = l.lookupSoftKeyword(doubleToken.Literal)
+			}
 
 			if doubleToken.Type != token.Ident {
 				return doubleToken
@@ -116,7 +172,7 @@ func (l *Lexer) AdvanceToken() token.Token {
 			return tok
 
 		} else {
-			l.Errors.AddLexerError("Ký tự '"+string(l.ch)+"' không hợp lệ", token.Token{
+			l.Errors.AddLexerError(errorhandler.INVALID_CHARACTER, errorhandler.InvalidCharacter.With(string(l.ch)), token.Token{
 				Type:    token.Illegal,
 				Literal: []rune{l.ch},
 				Line:    l.line,
@@ -130,6 +186,86 @@ func (l *Lexer) AdvanceToken() token.Token {
 	return tok
 }
 
+// lookupSoftKeyword reads a word like 'hợp' or 'lần' as a keyword only in its
+// context, so 'A hợp B' is a union but 'cho trường hợp = 1' names a variable
+func (l *Lexer) lookupSoftKeyword(word []rune) token.TokenType {
+	keyword, ok := token.LookupSoftKeyword(word)
+	if !ok {
+		return token.Ident
+	}
+
+	switch keyword.Context {
+	case token.RepeatHeader:
+		if l.inRepeatHeader {
+			return keyword.Type
+		}
+	case token.Infix:
+		if (l.isPreviousIdent || endsOperand(l.previousType)) && l.startsOperand() {
+			return keyword.Type
+		}
+	case token.StatementStart:
+		if !l.isPreviousIdent && startsStatement(l.previousType) {
+			return keyword.Type
+		}
+	case token.FunctionHead:
+		if !l.isPreviousIdent && l.startsFunction(startsStatement(l.previousType)) {
+			return keyword.Type
+		}
+	}
+	return token.Ident
+}
+
+// startsOperand tells if an operand follows the current position after some
+// spaces. A '(' right after the word is a call like 'tổ hợp(5, 2)'
+func (l *Lexer) startsOperand() bool {
+	pos := l.position
+	for pos < len(l.input) && (l.input[pos] == ' ' || l.input[pos] == '\t') {
+		pos++
+	}
+	if pos == l.position || pos == len(l.input) {
+		return false
+	}
+
+	ch := l.input[pos]
+	// a sign stuck to the operand like 'a mod -3', 'số bị trừ - 1' is a name
+	if ch == '-' && pos+1 < len(l.input) && l.input[pos+1] != ' ' && l.input[pos+1] != '\t' {
+		pos++
+		ch = l.input[pos]
+	}
+	switch {
+	case isDigit(ch) || ch == '(' || ch == '[' || ch == '{' || ch == '"':
+		return true
+	case isLetter(ch):
+		end := pos
+		for end < len(l.input) && isLetter(l.input[end]) {
+			end++
+		}
+		next := token.LookupKeyword(l.input[pos:end])
+		return next == token.Ident || next == token.True || next == token.False
+	}
+	return false
+}
+
+// startsFunction tells if a '(' follows the current position, or a name and
+// a '(' when withName is set, so 'hàm f(x):' is a function but not 'hàm số'
+func (l *Lexer) startsFunction(withName bool) bool {
+	pos := l.position
+	for pos < len(l.input) && (l.input[pos] == ' ' || l.input[pos] == '\t') {
+		pos++
+	}
+	if pos < len(l.input) && l.input[pos] == '(' {
+		return true
+	}
+	if !withName || pos == l.position || pos == len(l.input) || !isLetter(l.input[pos]) {
+		return false
+	}
+
+	for pos < len(l.input) && (isLetter(l.input[pos]) || l.input[pos] == ' ' || l.input[pos] == '\t') {
+		pos++
+	}
+	return pos < len(l.input) && l.input[pos] == '('
+}
+
 func (l *Lexer) newToken(tokenType token.TokenType, tokenLiteral []rune) token.Token {
 	return token.Token{
 		Type:    tokenType,
@@ -164,16 +300,20 @@ func (l *Lexer) consumeIdent() []rune {
 	for isLetter(l.ch) {
 		l.readChar()
 	}
+	// primes like x', f''
+	for l.position > pos && l.ch == '\'' {
+		l.readChar()
+	}
 	return l.input[pos:l.position]
 }
 
 func (l *Lexer) consumeString() []rune {
-	pos := l.position + 1
+	str := []rune{}
 	for {
 		l.readChar()
 
 		if l.ch == 0 || l.ch == '\n' || l.ch == ';' {
-			l.Errors.AddLexerError("thiếu dấu \" kết thúc chuỗi", token.Token{
+			l.Errors.AddLexerError(errorhandler.UNTERMINATED, errorhandler.UnterminatedString, token.Token{
 				Line:   l.line,
 				Column: l.column,
 			})
@@ -183,14 +323,132 @@ func (l *Lexer) consumeString() []rune {
 
 		} else if l.ch == '"' {
 			break
+
+		} else if l.ch == '\\' && l.peekChar() != 0 && l.peekChar() != '\n' {
+			l.readChar()
+			str = append(str, l.consumeEscape()...)
+
+		} else {
+			str = append(str, l.ch)
 		}
 	}
-	return l.input[pos:l.position]
+	return str
+}
+
+func (l *Lexer) consumeEscape() []rune {
+	if ch, ok := ESCAPE_CHARS[l.ch]; ok {
+		return []rune{ch}
+	}
+	if l.ch == 'u' {
+		return l.consumeUnicodeEscape()
+	}
+
+	l.Errors.AddLexerError(errorhandler.INVALID_CHARACTER, errorhandler.InvalidEscape.With(string(l.ch)), token.Token{
+		Type:    token.Illegal,
+		Literal: []rune{l.ch},
+		Line:    l.line,
+		Column:  l.column,
+	})
+	return []rune{}
+}
+
+// consumeUnicodeEscape reads the code point of "\\u{1F600}" or "\\uXXXX",
+// the lexer is expected to stand at the 'u'.
+func (l *Lexer) consumeUnicodeEscape() []rune {
+	column := l.column - 1
+	literal := []rune{'\\', 'u'}
+	digits := []rune{}
+
+	if l.peekChar() == '{' {
+		l.readChar()
+		literal = append(literal, l.ch)
+		for isHexDigit(l.peekChar()) {
+			l.readChar()
+			literal = append(literal, l.ch)
+			digits = append(digits, l.ch)
+		}
+		if l.peekChar() == '}' {
+			l.readChar()
+			literal = append(literal, l.ch)
+		} else {
+			digits = []rune{}
+		}
+	} else {
+		for i := 0; i < 4 && isHexDigit(l.peekChar()); i++ {
+			l.readChar()
+			literal = append(literal, l.ch)
+			digits = append(digits, l.ch)
+		}
+		if len(digits) != 4 {
+			digits = []rune{}
+		}
+	}
+
+	codePoint, err := strconv.ParseUint(string(digits), 16, 32)
+	if err != nil || !utf8.ValidRune(rune(codePoint)) {
+		l.Errors.AddLexerError(errorhandler.INVALID_CHARACTER, errorhandler.InvalidUnicode.With(string(literal)), token.Token{
+			Type:    token.Illegal,
+			Literal: literal,
+			Line:    l.line,
+			Column:  column,
+		})
+		return []rune{}
+	}
+	return []rune{rune(codePoint)}
 }
 
+// consumeNumber also takes the '_' separators like 1_000, the parser checks
+// their placement
 func (l *Lexer) consumeNumber() []rune {
 	pos := l.position
-	for isDigit(l.ch) {
+	for isDigit(l.ch) || l.ch == '_' {
+		l.readChar()
+	}
+	return l.input[pos:l.position]
+}
+
+// consumeExponent takes the exponent part of 1.6e-19, a lone 'e' isn't one
+// since 2e still means 2 * e
+func (l *Lexer) consumeExponent() []rune {
+	if l.ch != 'e' && l.ch != 'E' {
+		return nil
+	}
+	next := l.peekChar()
+	hasSign := next == '+' || next == '-'
+	if hasSign {
+		next = l.peekPeekChar()
+	}
+
+	if !isDigit(next) {
+		if hasSign && (next == 0 || unicode.IsSpace(next)) {
+			pos := l.position
+			column := l.column
+			l.readChar()
+			l.readChar()
+			l.Errors.AddLexerError(errorhandler.INVALID_NUMBER, errorhandler.MissingExponent.With(string(l.input[pos:l.position])), token.Token{
+				Type:    token.Illegal,
+				Literal: l.input[pos:l.position],
+				Line:    l.line,
+				Column:  column,
+			})
+		}
+		return nil
+	}
+
+	pos := l.position
+	l.readChar()
+	if hasSign {
+		l.readChar()
+	}
+	l.consumeNumber()
+	return l.input[pos:l.position]
+}
+
+func (l *Lexer) consumeBasedNumber() []rune {
+	pos := l.position
+	l.readChar()
+	l.readChar()
+	for isLetter(l.ch) {
 		l.readChar()
 	}
 	return l.input[pos:l.position]
@@ -217,6 +475,13 @@ func (l *Lexer) peekChar() rune {
 	}
 }
 
+func (l *Lexer) peekPeekChar() rune {
+	if l.readPosition+1 >= len(l.input) {
+		return 0
+	}
+	return l.input[l.readPosition+1]
+}
+
 func (l *Lexer) skipWhiteSpace() {
 	for l.ch == ' ' || l.ch == '\t' || l.ch == '\r' {
 		l.readChar()
@@ -228,3 +493,37 @@ func (l *Lexer) skipComment() {
 		l.readChar()
 	}
 }
+
+// skipBlockComment skips a (possibly nested) comment "(* ... *)" or a comment
+// "/* ... */" which doesn't nest like in C, the lexer is expected to stand at
+// the '*' of the opening token.
+func (l *Lexer) skipBlockComment(open token.Token) {
+	closing := []rune(token.AsteriskRParen)
+	if open.Type == token.SlashAsterisk {
+		closing = []rune(token.AsteriskSlash)
+	}
+	depth := 1
+	l.readChar()
+
+	for depth > 0 {
+		switch {
+		case l.ch == 0:
+			errMsg := errorhandler.UnterminatedComment.With(string(closing))
+			l.Errors.AddLexerError(errorhandler.UNTERMINATED, errMsg, open)
+			return
+
+		case open.Type == token.LParenAsterisk && l.ch == '(' && l.peekChar() == '*':
+			depth++
+			l.readChar()
+
+		case l.ch == closing[0] && l.peekChar() == closing[1]:
+			depth--
+			l.readChar()
+
+		case l.ch == '\n':
+			l.line += 1
+			l.column = 0
+		}
+		l.readChar()
+	}
+}
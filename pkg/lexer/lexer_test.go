@@ -0,0 +1,498 @@
+package lexer
+
+import (
+	"testing"
+	"vanvo/pkg/errorhandler"
+	"vanvo/pkg/token"
+)
+
+type expectedToken struct {
+	Type    token.TokenType
+	Literal string
+}
+
+func testTokens(t *testing.T, input string, expected []expectedToken) {
+	errors := errorhandler.NewErrorList(input, "")
+	l := New(input, errors)
+
+	for i, exp := range expected {
+		tok := l.AdvanceToken()
+
+		if tok.Type != exp.Type {
+			t.Fatalf("input %q, token %d has wrong type. want=%q, got=%q", input, i, exp.Type, tok.Type)
+		}
+		if string(tok.Literal) != exp.Literal {
+			t.Fatalf("input %q, token %d has wrong literal. want=%q, got=%q", input, i, exp.Literal, string(tok.Literal))
+		}
+	}
+
+	if errors.NotEmpty() {
+		t.Fatalf("input %q has errors: \n%s", input, errors)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"x'", []expectedToken{{token.Ident, "x'"}, {token.EOF, ""}}},
+		{"f''(x)", []expectedToken{
+			{token.Ident, "f''"}, {token.LParen, "("}, {token.Ident, "x"}, {token.RParen, ")"},
+		}},
+		{"a_1 = 2", []expectedToken{{token.Ident, "a_1"}, {token.Assign, "="}, {token.Int, "2"}}},
+		{"__tmp", []expectedToken{{token.Ident, "__tmp"}, {token.EOF, ""}}},
+		{"số nguyên tố", []expectedToken{{token.Ident, "số nguyên tố"}, {token.EOF, ""}}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+func TestSpacedIdentifierPosition(t *testing.T) {
+	errors := errorhandler.NewErrorList("", "")
+	l := New("cho a = 1\n  số nguyên tố(2)", errors)
+
+	tok := l.AdvanceToken()
+	for tok.Type != token.Ident || string(tok.Literal) != "số nguyên tố" {
+		tok = l.AdvanceToken()
+	}
+	if tok.Line != 2 || tok.Column != 3 {
+		t.Errorf("wrong position of %q. want line 2, column 3, got line %d, column %d",
+			string(tok.Literal), tok.Line, tok.Column)
+	}
+}
+
+func TestSetOperatorSymbol(t *testing.T) {
+	testTokens(t, `A ∪ B ∩ C \ D ∖ E`, []expectedToken{
+		{token.Ident, "A"}, {token.Union, "∪"}, {token.Ident, "B"}, {token.Intersect, "∩"},
+		{token.Ident, "C"}, {token.SetMinus, `\`}, {token.Ident, "D"}, {token.SetMinus, "∖"},
+		{token.Ident, "E"}, {token.EOF, ""},
+	})
+}
+
+func TestBelongSymbol(t *testing.T) {
+	testTokens(t, "x ∈ A", []expectedToken{
+		{token.Ident, "x"}, {token.Belong, "∈"}, {token.Ident, "A"}, {token.EOF, ""},
+	})
+}
+
+func TestBasedNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"0x1F + 0b10", []expectedToken{{token.Int, "0x1F"}, {token.Plus, "+"}, {token.Int, "0b10"}}},
+		{"0o17", []expectedToken{{token.Int, "0o17"}, {token.EOF, ""}}},
+		{"0xZZ", []expectedToken{{token.Int, "0xZZ"}, {token.EOF, ""}}},
+		{"0x", []expectedToken{{token.Int, "0"}, {token.Ident, "x"}, {token.EOF, ""}}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+func TestDigitSeparator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"1_000_000", []expectedToken{{token.Int, "1_000_000"}, {token.EOF, ""}}},
+		{"3.141_592", []expectedToken{{token.Real, "3.141_592"}, {token.EOF, ""}}},
+		{"5__0", []expectedToken{{token.Int, "5__0"}, {token.EOF, ""}}},
+		{"_5", []expectedToken{{token.Ident, "_5"}, {token.EOF, ""}}},
+		{"1.2.3", []expectedToken{{token.Real, "1.2.3"}, {token.EOF, ""}}},
+		{"[1.5..3]", []expectedToken{
+			{token.LBracket, "["}, {token.Real, "1.5"}, {token.DotDot, ".."}, {token.Int, "3"}, {token.RBracket, "]"},
+		}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+func TestImaginaryLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"3i", []expectedToken{{token.Imag, "3i"}, {token.EOF, ""}}},
+		{"2.5i", []expectedToken{{token.Imag, "2.5i"}, {token.EOF, ""}}},
+		{"1e3i", []expectedToken{{token.Imag, "1e3i"}, {token.EOF, ""}}},
+		{"2 + 3i", []expectedToken{{token.Int, "2"}, {token.Plus, "+"}, {token.Imag, "3i"}, {token.EOF, ""}}},
+		{"(1-2i)", []expectedToken{
+			{token.LParen, "("}, {token.Int, "1"}, {token.Minus, "-"}, {token.Imag, "2i"}, {token.RParen, ")"},
+		}},
+		{"3in", []expectedToken{{token.Int, "3"}, {token.Ident, "in"}, {token.EOF, ""}}},
+		{"3i2", []expectedToken{{token.Int, "3"}, {token.Ident, "i2"}, {token.EOF, ""}}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+func TestScientificNotation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"6.02e23", []expectedToken{{token.Real, "6.02e23"}, {token.EOF, ""}}},
+		{"1.6e-19", []expectedToken{{token.Real, "1.6e-19"}, {token.EOF, ""}}},
+		{"1e3", []expectedToken{{token.Real, "1e3"}, {token.EOF, ""}}},
+		{"2E+3", []expectedToken{{token.Real, "2E+3"}, {token.EOF, ""}}},
+		{"2E-3", []expectedToken{{token.Real, "2E-3"}, {token.EOF, ""}}},
+		{"1_000.5e1_0", []expectedToken{{token.Real, "1_000.5e1_0"}, {token.EOF, ""}}},
+		{"2e", []expectedToken{{token.Int, "2"}, {token.Ident, "e"}, {token.EOF, ""}}},
+		{"2e - 1", []expectedToken{{token.Int, "2"}, {token.Ident, "e"}, {token.Minus, "-"}}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+func TestDanglingExponent(t *testing.T) {
+	for _, input := range []string{"5e+", "5e- 1"} {
+		errors := errorhandler.NewErrorList(input, "")
+		l := New(input, errors)
+		for tok := l.AdvanceToken(); tok.Type != token.EOF; tok = l.AdvanceToken() {
+		}
+
+		if len(errors.LexerErrors) != 1 {
+			t.Fatalf("input %q expected 1 lexer error, got %d", input, len(errors.LexerErrors))
+		}
+		err := errors.LexerErrors[0]
+		if err.Token.Column != 2 {
+			t.Errorf("input %q has wrong error column. want=2, got=%d", input, err.Token.Column)
+		}
+	}
+}
+
+func TestEndlineIndent(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"a\n    b", []expectedToken{{token.Ident, "a"}, {token.Endline, "    "}, {token.Ident, "b"}}},
+		{"a\n\tb", []expectedToken{{token.Ident, "a"}, {token.Endline, "    "}, {token.Ident, "b"}}},
+		{"a\n  \n    b", []expectedToken{
+			{token.Ident, "a"}, {token.Endline, "  "}, {token.Endline, "    "}, {token.Ident, "b"},
+		}},
+		{"a   \nb", []expectedToken{{token.Ident, "a"}, {token.Endline, ""}, {token.Ident, "b"}}},
+		{"a\n    b\n        c\n    d\ne", []expectedToken{
+			{token.Ident, "a"}, {token.Endline, "    "}, {token.Ident, "b"}, {token.Endline, "        "},
+			{token.Ident, "c"}, {token.Endline, "    "}, {token.Ident, "d"}, {token.Endline, ""}, {token.Ident, "e"},
+		}},
+		{"a\n\t\tb\n\t  c", []expectedToken{
+			{token.Ident, "a"}, {token.Endline, "        "}, {token.Ident, "b"}, {token.Endline, "      "}, {token.Ident, "c"},
+		}},
+		{"a\r\n    b\r\n  \r\n        c", []expectedToken{
+			{token.Ident, "a"}, {token.Endline, "    "}, {token.Ident, "b"},
+			{token.Endline, "  "}, {token.Endline, "        "}, {token.Ident, "c"},
+		}},
+		{"a\n        \n\n    b", []expectedToken{
+			{token.Ident, "a"}, {token.Endline, "        "}, {token.Endline, ""}, {token.Endline, "    "}, {token.Ident, "b"},
+		}},
+		{"a\n    ", []expectedToken{{token.Ident, "a"}, {token.Endline, "    "}, {token.EOF, ""}}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+func TestKeywordWithoutDiacritic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"dừng", []expectedToken{{token.Break, "dừng"}, {token.EOF, ""}}},
+		{"dung", []expectedToken{{token.True, "dung"}, {token.EOF, ""}}},
+		{"tiep", []expectedToken{{token.Ident, "tiep"}, {token.EOF, ""}}},
+		{"lap 3 lan", []expectedToken{{token.Ident, "lap"}, {token.Int, "3"}, {token.Ident, "lan"}}},
+		{"voi moi", []expectedToken{{token.ForEach, "voi moi"}, {token.EOF, ""}}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+func TestSoftKeyword(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"A hợp B", []expectedToken{{token.Ident, "A"}, {token.Union, "hợp"}, {token.Ident, "B"}}},
+		{"{1} giao {2}", []expectedToken{
+			{token.LBrace, "{"}, {token.Int, "1"}, {token.RBrace, "}"}, {token.Intersect, "giao"}, {token.LBrace, "{"},
+		}},
+		{"trường hợp = 1", []expectedToken{{token.Ident, "trường hợp"}, {token.Assign, "="}, {token.Int, "1"}}},
+		{"tổ hợp(5, 2)", []expectedToken{{token.Ident, "tổ hợp"}, {token.LParen, "("}}},
+		{"số bị trừ - 1", []expectedToken{{token.Ident, "số bị trừ"}, {token.Minus, "-"}}},
+		{"A hợp và", []expectedToken{{token.Ident, "A hợp"}, {token.And, "và"}}},
+		{"cho giao điểm", []expectedToken{{token.Let, "cho"}, {token.Ident, "giao điểm"}, {token.EOF, ""}}},
+		{"x là số nguyên", []expectedToken{{token.Ident, "x"}, {token.Is, "là"}, {token.Ident, "số nguyên"}}},
+		{"(1 + I) là Số Phức", []expectedToken{
+			{token.LParen, "("}, {token.Int, "1"}, {token.Plus, "+"}, {token.Ident, "I"}, {token.RParen, ")"}, {token.Is, "là"},
+		}},
+		{"cho là chẵn(n)", []expectedToken{{token.Let, "cho"}, {token.Ident, "là chẵn"}, {token.LParen, "("}}},
+		{"nếu là ngtố(n):", []expectedToken{{token.If, "nếu"}, {token.Ident, "là ngtố"}, {token.LParen, "("}}},
+		{"cho la = 2", []expectedToken{{token.Let, "cho"}, {token.Ident, "la"}, {token.Assign, "="}}},
+		{"số lần", []expectedToken{{token.Ident, "số lần"}, {token.EOF, ""}}},
+		{"lặp n lần:", []expectedToken{{token.Repeat, "lặp"}, {token.Ident, "n"}, {token.Times, "lần"}, {token.Colon, ":"}}},
+		{"lặp i từ 1 đến n bước 2", []expectedToken{
+			{token.Repeat, "lặp"}, {token.Ident, "i"}, {token.From, "từ"}, {token.Int, "1"},
+			{token.To, "đến"}, {token.Ident, "n"}, {token.Step, "bước"}, {token.Int, "2"},
+		}},
+		{"lặp 2 lần: số lần", []expectedToken{
+			{token.Repeat, "lặp"}, {token.Int, "2"}, {token.Times, "lần"}, {token.Colon, ":"}, {token.Ident, "số lần"},
+		}},
+		{"cho từ khóa = đến hạn", []expectedToken{
+			{token.Let, "cho"}, {token.Ident, "từ khóa"}, {token.Assign, "="}, {token.Ident, "đến hạn"},
+		}},
+		{"dừng", []expectedToken{{token.Break, "dừng"}, {token.EOF, ""}}},
+		{"nếu x: tiếp", []expectedToken{{token.If, "nếu"}, {token.Ident, "x"}, {token.Colon, ":"}, {token.Continue, "tiếp"}}},
+		{"cho tiếp tuyến = 1", []expectedToken{{token.Let, "cho"}, {token.Ident, "tiếp tuyến"}, {token.Assign, "="}}},
+		{"hệ số góc tiếp tuyến", []expectedToken{{token.Ident, "hệ số góc tiếp tuyến"}, {token.EOF, ""}}},
+		{"trong khi x:", []expectedToken{{token.While, "trong khi"}, {token.Ident, "x"}, {token.Colon, ":"}}},
+		{"ngoài: khi x:", []expectedToken{{token.Ident, "ngoài"}, {token.Colon, ":"}, {token.While, "khi"}, {token.Ident, "x"}}},
+		{"cho thời điểm khi = 1", []expectedToken{{token.Let, "cho"}, {token.Ident, "thời điểm khi"}, {token.Assign, "="}}},
+		{"cho trong khi đó = 1", []expectedToken{{token.Let, "cho"}, {token.Ident, "trong khi đó"}, {token.Assign, "="}}},
+		{"thoát ngoài", []expectedToken{{token.Break, "thoát"}, {token.Ident, "ngoài"}, {token.EOF, ""}}},
+		{"cho lối thoát = 1", []expectedToken{{token.Let, "cho"}, {token.Ident, "lối thoát"}, {token.Assign, "="}}},
+		{"a mod 5", []expectedToken{{token.Ident, "a"}, {token.Percent, "mod"}, {token.Int, "5"}}},
+		{"7 mod -3", []expectedToken{{token.Int, "7"}, {token.Percent, "mod"}, {token.Minus, "-"}, {token.Int, "3"}}},
+		{"cho mod = 3", []expectedToken{{token.Let, "cho"}, {token.Ident, "mod"}, {token.Assign, "="}, {token.Int, "3"}}},
+		{"hàm f(x):", []expectedToken{{token.Func, "hàm"}, {token.Ident, "f"}, {token.LParen, "("}}},
+		{"f = hàm(x) = x", []expectedToken{{token.Ident, "f"}, {token.Assign, "="}, {token.Func, "hàm"}, {token.LParen, "("}}},
+		{"cho hàm số = 3", []expectedToken{{token.Let, "cho"}, {token.Ident, "hàm số"}, {token.Assign, "="}}},
+		{"hàm số + 1", []expectedToken{{token.Ident, "hàm số"}, {token.Plus, "+"}}},
+		{"x = điểm dừng", []expectedToken{{token.Ident, "x"}, {token.Assign, "="}, {token.Ident, "điểm dừng"}}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+func TestBooleanLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"đúng", []expectedToken{{token.True, "đúng"}, {token.EOF, ""}}},
+		{"sai", []expectedToken{{token.False, "sai"}, {token.EOF, ""}}},
+		{"true", []expectedToken{{token.True, "true"}, {token.EOF, ""}}},
+		{"false", []expectedToken{{token.False, "false"}, {token.EOF, ""}}},
+		{"true và false", []expectedToken{{token.True, "true"}, {token.And, "và"}, {token.False, "false"}}},
+		{"trueish", []expectedToken{{token.Ident, "trueish"}, {token.EOF, ""}}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+func TestStringEscape(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"\t"`, "\t"},
+		{`"a\nb"`, "a\nb"},
+		{`"C:\\vanvo"`, `C:\vanvo`},
+		{`"nói \"xin chào\""`, `nói "xin chào"`},
+		{`"không thoát"`, "không thoát"},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, []expectedToken{{token.String, test.expected}, {token.EOF, ""}})
+	}
+}
+
+func TestInvalidStringEscape(t *testing.T) {
+	input := `"ab\qc"`
+	errors := errorhandler.NewErrorList(input, "")
+	l := New(input, errors)
+	l.AdvanceToken()
+
+	if len(errors.LexerErrors) != 1 {
+		t.Fatalf("expected 1 lexer error, got=%d", len(errors.LexerErrors))
+	}
+	if col := errors.LexerErrors[0].Token.Column; col != 5 {
+		t.Errorf("error has wrong column. want=5, got=%d", col)
+	}
+}
+
+func TestComment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"x // chú thích", []expectedToken{{token.Ident, "x"}, {token.EOF, ""}}},
+		{"x (* chú thích *)", []expectedToken{{token.Ident, "x"}, {token.EOF, ""}}},
+		{"(* a (* lồng *) b *) x", []expectedToken{{token.Ident, "x"}, {token.EOF, ""}}},
+		{"x /* chú thích */", []expectedToken{{token.Ident, "x"}, {token.EOF, ""}}},
+		{"/* a (* */ x", []expectedToken{{token.Ident, "x"}, {token.EOF, ""}}},
+		{"x /* dòng 1\ndòng 2 */ + 1", []expectedToken{
+			{token.Ident, "x"}, {token.Plus, "+"}, {token.Int, "1"}, {token.EOF, ""},
+		}},
+		{"x / 2", []expectedToken{{token.Ident, "x"}, {token.Slash, "/"}, {token.Int, "2"}, {token.EOF, ""}}},
+		{"x (* dòng 1\ndòng 2 *) + 1", []expectedToken{
+			{token.Ident, "x"}, {token.Plus, "+"}, {token.Int, "1"}, {token.EOF, ""},
+		}},
+		{"nếu x:\n    (* chú thích *)\n    y", []expectedToken{
+			{token.If, "nếu"}, {token.Ident, "x"}, {token.Colon, ":"},
+			{token.Endline, "    "}, {token.Endline, "    "}, {token.Ident, "y"},
+		}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+func TestTrailingComment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"x = 1  // chú thích", []expectedToken{
+			{token.Ident, "x"}, {token.Assign, "="}, {token.Int, "1"}, {token.EOF, ""},
+		}},
+		{"x = 1 // a + b\ny", []expectedToken{
+			{token.Ident, "x"}, {token.Assign, "="}, {token.Int, "1"}, {token.Endline, ""}, {token.Ident, "y"},
+		}},
+		{"x = 1 (* a *) + 2", []expectedToken{
+			{token.Ident, "x"}, {token.Assign, "="}, {token.Int, "1"}, {token.Plus, "+"}, {token.Int, "2"},
+		}},
+		{"nếu x: // điều kiện\n    y = 1 // gán\n    z", []expectedToken{
+			{token.If, "nếu"}, {token.Ident, "x"}, {token.Colon, ":"}, {token.Endline, "    "},
+			{token.Ident, "y"}, {token.Assign, "="}, {token.Int, "1"}, {token.Endline, "    "}, {token.Ident, "z"},
+		}},
+		{"x = 1 //\r\n  y", []expectedToken{
+			{token.Ident, "x"}, {token.Assign, "="}, {token.Int, "1"}, {token.Endline, "  "}, {token.Ident, "y"},
+		}},
+		{`x = "// không phải chú thích"`, []expectedToken{
+			{token.Ident, "x"}, {token.Assign, "="}, {token.String, "// không phải chú thích"}, {token.EOF, ""},
+		}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+// A comment line may be indented anyhow, only the line after it sets the
+// level of the block
+func TestCommentIndent(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []expectedToken
+	}{
+		{"nếu x:\n        // sâu hơn\n    y", []expectedToken{
+			{token.If, "nếu"}, {token.Ident, "x"}, {token.Colon, ":"},
+			{token.Endline, "        "}, {token.Endline, "    "}, {token.Ident, "y"},
+		}},
+		{"nếu x:\n\t// tab\n\ty", []expectedToken{
+			{token.If, "nếu"}, {token.Ident, "x"}, {token.Colon, ":"},
+			{token.Endline, "    "}, {token.Endline, "    "}, {token.Ident, "y"},
+		}},
+		{"nếu x:\n    (* dòng 1\n  dòng 2 *)\n    y", []expectedToken{
+			{token.If, "nếu"}, {token.Ident, "x"}, {token.Colon, ":"},
+			{token.Endline, "    "}, {token.Endline, "    "}, {token.Ident, "y"},
+		}},
+		{"nếu x:\n    y (* a\nb *) + 1\n    z", []expectedToken{
+			{token.If, "nếu"}, {token.Ident, "x"}, {token.Colon, ":"}, {token.Endline, "    "},
+			{token.Ident, "y"}, {token.Plus, "+"}, {token.Int, "1"}, {token.Endline, "    "}, {token.Ident, "z"},
+		}},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, test.expected)
+	}
+}
+
+func TestBlockCommentPosition(t *testing.T) {
+	input := "(* dòng 1\ndòng 2 *) x"
+	errors := errorhandler.NewErrorList(input, "")
+	tok := New(input, errors).AdvanceToken()
+
+	if tok.Line != 2 || tok.Column != 11 {
+		t.Errorf("token has wrong position. want=(2, 11), got=(%d, %d)", tok.Line, tok.Column)
+	}
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+	}{
+		{"x (* chưa đóng (* *)", "Thiếu '*)' để kết thúc chú thích"},
+		{"x /* chưa đóng", "Thiếu '*/' để kết thúc chú thích"},
+		{"x /* chưa đóng *)", "Thiếu '*/' để kết thúc chú thích"},
+	}
+
+	for _, test := range tests {
+		errors := errorhandler.NewErrorList(test.input, "")
+		l := New(test.input, errors)
+
+		for tok := l.AdvanceToken(); tok.Type != token.EOF; tok = l.AdvanceToken() {
+		}
+
+		if len(errors.LexerErrors) != 1 {
+			t.Fatalf("input %q expected 1 lexer error, got=%d", test.input, len(errors.LexerErrors))
+		}
+		if col := errors.LexerErrors[0].Token.Column; col != 3 {
+			t.Errorf("input %q error has wrong column. want=3, got=%d", test.input, col)
+		}
+		if message := errors.LexerErrors[0].Message; message != test.message {
+			t.Errorf("input %q error has wrong message. want=%q, got=%q", test.input, test.message, message)
+		}
+	}
+}
+
+func TestUnicodeEscape(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"\u{1F600}"`, "😀"},
+		{`"\u{41}\u{42}"`, "AB"},
+		{`"Vi\u1EC7t"`, "Việt"},
+		{`"\u00e9t\u00E9"`, "été"},
+	}
+
+	for _, test := range tests {
+		testTokens(t, test.input, []expectedToken{{token.String, test.expected}, {token.EOF, ""}})
+	}
+}
+
+func TestInvalidUnicodeEscape(t *testing.T) {
+	tests := []string{
+		`"\u{110000}"`,
+		`"\u{D800}"`,
+		`"\u{}"`,
+		`"\u{41"`,
+		`"\u12"`,
+		`"\uZZZZ"`,
+	}
+
+	for _, input := range tests {
+		errors := errorhandler.NewErrorList(input, "")
+		New(input, errors).AdvanceToken()
+
+		if len(errors.LexerErrors) == 0 {
+			t.Errorf("input %q expected a lexer error, got none", input)
+			continue
+		}
+		if col := errors.LexerErrors[0].Token.Column; col != 2 {
+			t.Errorf("input %q error has wrong column. want=2, got=%d", input, col)
+		}
+	}
+}
@@ -34,7 +34,17 @@ var TOKEN_TABLE = map[string]token.TokenType{
 	">=": token.GreaterEqual,
 	"=>": token.Imply,
 	"//": token.SlashSlash,
+	"(*": token.LParenAsterisk,
+	"/*": token.SlashAsterisk,
 	"|":  token.Bar,
+	"&":  token.Ampersand,
+	"<<": token.LessLess,
+	">>": token.GreaterGreater,
+	"∈":  token.Belong,
+	"∪":  token.Union,
+	"∩":  token.Intersect,
+	"\\": token.SetMinus,
+	"∖":  token.SetMinus,
 }
 
 func (l *Lexer) lookupToken() token.Token {
@@ -42,12 +52,13 @@ func (l *Lexer) lookupToken() token.Token {
 	ch := string(l.ch)
 
 	if tokenType, ok := TOKEN_TABLE[doubleCh]; ok {
+		column := l.column
 		l.readChar()
 		return token.Token{
 			Type:    tokenType,
 			Literal: []rune(doubleCh),
 			Line:    l.line,
-			Column:  l.column,
+			Column:  column,
 		}
 	} else if tokenType, ok := TOKEN_TABLE[ch]; ok {
 		return token.Token{
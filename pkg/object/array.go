@@ -0,0 +1,49 @@
+package object
+
+const (
+	ArrayObj = "Mảng"
+)
+
+type Array struct {
+	Data []Object
+}
+
+func (arr *Array) Type() ObjectType { return ArrayObj }
+func (arr *Array) Display() string {
+	return displayElements("[", "]", arr.Data)
+}
+func (arr *Array) IsCountable() bool { return true }
+func (arr *Array) Contain(obj Object) *Boolean {
+	if obj, ok := obj.(Equal); ok {
+		for _, each := range arr.Data {
+			if obj.Equal(each).Value {
+				return TRUE
+			}
+		}
+	}
+	return FALSE
+}
+func (arr *Array) At(index int) Object {
+	if index < 0 || index >= len(arr.Data) {
+		return IndexError
+	}
+	return arr.Data[index]
+}
+func (arr *Array) SetAt(index int, value Object) Object {
+	if index < 0 || index >= len(arr.Data) {
+		return IndexError
+	}
+	arr.Data[index] = value
+	return value
+}
+func (arr *Array) Length() int {
+	return len(arr.Data)
+}
+func (arr *Array) Iterate(callback IterateCallback) {
+	for _, each := range arr.Data {
+		val := callback(each)
+		if StopsIteration(val) {
+			break
+		}
+	}
+}
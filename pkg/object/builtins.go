@@ -4,18 +4,21 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strings"
+	"unicode/utf8"
+	"vanvo/pkg/errorhandler"
 )
 
 const (
 	ErrorObj = "Lỗi"
 )
 
-func NewError(message string) *Error {
+func NewError(message errorhandler.Message) *Error {
 	return &Error{Message: message}
 }
 
 type Error struct {
-	Message string
+	Message errorhandler.Message
 }
 
 func (*Error) Type() ObjectType { return ErrorObj }
@@ -46,8 +49,12 @@ var Builtins = map[string]Object{
 				val := big.NewInt(int64(arg.Length()))
 				return &Int{Value: val}
 
+			} else if arg, ok := args[0].(*String); ok {
+				val := big.NewInt(int64(arg.Length()))
+				return &Int{Value: val}
+
 			} else {
-				errMsg := fmt.Sprintf("Không thể dùng '%s' làm tham số", args[0].Type())
+				errMsg := errorhandler.InvalidArgument.With(args[0].Type())
 				return NewError(errMsg)
 			}
 		},
@@ -55,6 +62,9 @@ var Builtins = map[string]Object{
 	"căn": &Function{
 		Builtin: SquareRootBuiltin,
 	},
+	"mô_đun": &Function{
+		Builtin: moduleBuiltin,
+	},
 	"sin": &Function{
 		Builtin: sinBuiltin,
 	},
@@ -67,6 +77,72 @@ var Builtins = map[string]Object{
 	"ln": &Function{
 		Builtin: naturalLogBuiltin,
 	},
+	"sang_radian": &Function{
+		Builtin: angleBuiltin(big.NewFloat(math.Pi), big.NewFloat(180)),
+	},
+	"sang_độ": &Function{
+		Builtin: angleBuiltin(big.NewFloat(180), big.NewFloat(math.Pi)),
+	},
+	"thay_thế": &Function{
+		Builtin: replaceBuiltin,
+	},
+	"bắt_đầu_bằng": &Function{
+		Builtin: hasPrefixBuiltin,
+	},
+	"kết_thúc_bằng": &Function{
+		Builtin: hasSuffixBuiltin,
+	},
+	"bảng giá trị": &Function{
+		HigherOrder: tabulateBuiltin,
+	},
+	"ánh xạ": &Function{
+		HigherOrder: mapBuiltin,
+	},
+	"gom_nhóm": &Function{
+		HigherOrder: groupBuiltin,
+	},
+	"ghép_cặp": &Function{
+		Builtin: zipBuiltin,
+	},
+	"nhị_phân": &Function{
+		Builtin: baseBuiltin(2),
+	},
+	"thập_lục": &Function{
+		Builtin: baseBuiltin(16),
+	},
+	"ký_tự": &Function{
+		Builtin: charBuiltin,
+	},
+	"mã": &Function{
+		Builtin: codePointBuiltin,
+	},
+	"sao chép": &Function{
+		Builtin: copyBuiltin,
+	},
+	"sao_chép": &Function{
+		Builtin: copyBuiltin,
+	},
+	"xấp xỉ": &Function{
+		Builtin: approxBuiltin,
+	},
+	"hữu tỉ": &Function{
+		Builtin: rationalBuiltin,
+	},
+	"số thực": &Function{
+		Builtin: realBuiltin,
+	},
+	"khoảng_cách": &Function{
+		Builtin: distanceBuiltin,
+	},
+	"sốLượng": &Function{
+		Builtin: cardinalityBuiltin,
+	},
+	"sắp_xếp": &Function{
+		Builtin: sortBuiltin,
+	},
+	"in_lỗi": &Function{
+		Writer: printErrorBuiltin,
+	},
 }
 
 func SquareRootBuiltin(args ...Object) Object {
@@ -77,11 +153,29 @@ func SquareRootBuiltin(args ...Object) Object {
 		return arg.ToReal().Sqrt()
 
 	} else {
-		errMsg := fmt.Sprintf("Không thể dùng '%s' làm tham số", args[0].Type())
+		errMsg := errorhandler.InvalidArgument.With(args[0].Type())
 		return NewError(errMsg)
 	}
 }
 
+// moduleBuiltin is |z| of a complex number, or the absolute value of a real one
+func moduleBuiltin(args ...Object) Object {
+	if len(args) != 1 {
+		return NewArgumentError(1, args)
+	}
+	switch arg := args[0].(type) {
+	case *Complex:
+		return arg.Module()
+	case Realness:
+		if arg.Less(NewInt(IntZero)).Value {
+			return NewInt(big.NewInt(-1)).Multiply(arg)
+		}
+		return arg
+	default:
+		return invalidArgument(arg)
+	}
+}
+
 func sinBuiltin(args ...Object) Object {
 	if len(args) != 1 {
 		return NewArgumentError(1, args)
@@ -90,7 +184,7 @@ func sinBuiltin(args ...Object) Object {
 		return arg.ToReal().Sin()
 
 	} else {
-		errMsg := fmt.Sprintf("Không thể dùng '%s' làm tham số", args[0].Type())
+		errMsg := errorhandler.InvalidArgument.With(args[0].Type())
 		return NewError(errMsg)
 	}
 }
@@ -103,7 +197,7 @@ func cosBuiltin(args ...Object) Object {
 		return arg.ToReal().Cos()
 
 	} else {
-		errMsg := fmt.Sprintf("Không thể dùng '%s' làm tham số", args[0].Type())
+		errMsg := errorhandler.InvalidArgument.With(args[0].Type())
 		return NewError(errMsg)
 	}
 }
@@ -116,7 +210,7 @@ func tanBuiltin(args ...Object) Object {
 		return arg.ToReal().Tan()
 
 	} else {
-		errMsg := fmt.Sprintf("Không thể dùng '%s' làm tham số", args[0].Type())
+		errMsg := errorhandler.InvalidArgument.With(args[0].Type())
 		return NewError(errMsg)
 	}
 }
@@ -129,7 +223,515 @@ func naturalLogBuiltin(args ...Object) Object {
 		return arg.ToReal().NaturalLog()
 
 	} else {
-		errMsg := fmt.Sprintf("Không thể dùng '%s' làm tham số", args[0].Type())
+		errMsg := errorhandler.InvalidArgument.With(args[0].Type())
 		return NewError(errMsg)
 	}
 }
+
+// angleBuiltin converts an angle to another unit, the angle is multiplied by
+// to then divided by from: sang_radian(180) = 180 * Pi / 180
+func angleBuiltin(to, from *big.Float) func(args ...Object) Object {
+	return func(args ...Object) Object {
+		if len(args) != 1 {
+			return NewArgumentError(1, args)
+		}
+		angle, ok := args[0].(Realness)
+		if !ok {
+			return invalidArgument(args[0])
+		}
+		value := new(big.Float).Mul(angle.ToReal().Value, to)
+		return NewReal(value.Quo(value, from))
+	}
+}
+
+// baseBuiltin writes an integer in the given base, negative numbers keep
+// their sign: nhị_phân(-5) = "-101"
+func baseBuiltin(base int) func(args ...Object) Object {
+	return func(args ...Object) Object {
+		if len(args) != 1 {
+			return NewArgumentError(1, args)
+		}
+		n, ok := args[0].(*Int)
+		if !ok {
+			return invalidArgument(args[0])
+		}
+		return &String{Value: strings.ToUpper(n.Value.Text(base))}
+	}
+}
+
+// charBuiltin gives the one character string of a code point: ký_tự(65) = "A"
+func charBuiltin(args ...Object) Object {
+	if len(args) != 1 {
+		return NewArgumentError(1, args)
+	}
+	n, ok := args[0].(*Int)
+	if !ok {
+		return invalidArgument(args[0])
+	}
+	code := n.Value.Int64()
+	if !n.Value.IsInt64() || code > utf8.MaxRune || !utf8.ValidRune(rune(code)) {
+		return NewError(errorhandler.InvalidUnicode.With(n.Value.String()))
+	}
+	return &String{Value: string(rune(code))}
+}
+
+// codePointBuiltin gives the code point of the first character: mã("A") = 65
+func codePointBuiltin(args ...Object) Object {
+	if len(args) != 1 {
+		return NewArgumentError(1, args)
+	}
+	strs, err := stringArguments(args)
+	if err != nil {
+		return err
+	}
+	if strs[0] == "" {
+		return NewError(errorhandler.EmptyString)
+	}
+	ch, _ := utf8.DecodeRuneInString(strs[0])
+	return NewInt(big.NewInt(int64(ch)))
+}
+
+func copyBuiltin(args ...Object) Object {
+	if len(args) != 1 {
+		return NewArgumentError(1, args)
+	}
+	return DeepCopy(args[0])
+}
+
+// approxBuiltin compares two numbers with the tolerance Epsilon, it's the
+// safe way to check equality of reals
+func approxBuiltin(args ...Object) Object {
+	if len(args) != 2 {
+		return NewArgumentError(2, args)
+	}
+	a, ok := args[0].(Realness)
+	if !ok {
+		return invalidArgument(args[0])
+	}
+	b, ok := args[1].(Realness)
+	if !ok {
+		return invalidArgument(args[1])
+	}
+	x, y := a.ToReal().Value, b.ToReal().Value
+	// an infinity is only close to the same infinity
+	if x.IsInf() || y.IsInf() {
+		return Condition(x.IsInf() && y.IsInf() && x.Signbit() == y.Signbit())
+	}
+	dif := new(big.Float).Sub(x, y)
+	return Condition(dif.Abs(dif).Cmp(Epsilon) < 0)
+}
+
+// MaxDenominator bounds the fractions hữu tỉ looks for, a real that is only
+// close to a fraction with a bigger denominator isn't taken as rational
+const MaxDenominator = 10_000
+
+// rationalTolerance is how far a real can be from the fraction hữu tỉ finds,
+// it only forgives the rounding of the real itself
+var rationalTolerance = big.NewRat(1, 1_000_000_000_000)
+
+// rationalBuiltin turns a real that is a simple fraction back into the exact
+// fraction: hữu tỉ(0.25) = 1/4, hữu tỉ(1/3.0) = 1/3
+func rationalBuiltin(args ...Object) Object {
+	if len(args) != 1 {
+		return NewArgumentError(1, args)
+	}
+	switch arg := args[0].(type) {
+	case *Int, *Quotient:
+		return arg
+	case *Real:
+		if arg.Value.IsInf() {
+			return NewError(errorhandler.NotRational.With(arg.Display(), MaxDenominator))
+		}
+		value, _ := arg.Value.Rat(nil)
+		if fraction, ok := simplestFraction(value); ok {
+			return rational(fraction)
+		}
+		return NewError(errorhandler.NotRational.With(arg.Display(), MaxDenominator))
+	default:
+		return invalidArgument(arg)
+	}
+}
+
+// simplestFraction goes through the convergents of the continued fraction of
+// x and gives the first one within rationalTolerance of it
+func simplestFraction(x *big.Rat) (*big.Rat, bool) {
+	tolerance := new(big.Rat).Abs(x)
+	if tolerance.Cmp(big.NewRat(1, 1)) < 0 {
+		tolerance.SetInt64(1)
+	}
+	tolerance.Mul(tolerance, rationalTolerance)
+
+	// h/k is the convergent, the previous ones are kept to build the next
+	h, prevH := big.NewInt(1), big.NewInt(0)
+	k, prevK := big.NewInt(0), big.NewInt(1)
+	rest := new(big.Rat).Set(x)
+
+	for {
+		a := new(big.Int).Div(rest.Num(), rest.Denom())
+		h, prevH = new(big.Int).Add(new(big.Int).Mul(a, h), prevH), h
+		k, prevK = new(big.Int).Add(new(big.Int).Mul(a, k), prevK), k
+		if k.Cmp(big.NewInt(MaxDenominator)) > 0 {
+			return nil, false
+		}
+
+		convergent := new(big.Rat).SetFrac(h, k)
+		diff := new(big.Rat).Sub(convergent, x)
+		if diff.Abs(diff).Cmp(tolerance) <= 0 {
+			return convergent, true
+		}
+
+		rest.Sub(rest, new(big.Rat).SetInt(a))
+		rest.Inv(rest)
+	}
+}
+
+// realBuiltin gives the decimal form of a number, a fraction is rounded to
+// the nearest float64 so about 16 significant digits are kept
+func realBuiltin(args ...Object) Object {
+	if len(args) != 1 {
+		return NewArgumentError(1, args)
+	}
+	if arg, ok := args[0].(Realness); ok {
+		return arg.ToReal()
+	}
+	return invalidArgument(args[0])
+}
+
+func cardinalityBuiltin(args ...Object) Object {
+	if len(args) != 1 {
+		return NewArgumentError(1, args)
+	}
+	if m, ok := args[0].(*Map); ok {
+		return NewInt(big.NewInt(int64(m.Length())))
+	}
+	set, ok := args[0].(Set)
+	if !ok {
+		return invalidArgument(args[0])
+	}
+	return Cardinality(set)
+}
+
+// Cardinality counts the elements of a finite set, int intervals are counted
+// without enumerating them. Infinite sets give an error instead of looping
+func Cardinality(set Set) Object {
+	if _, countable := set.(CountableSet); !countable || !set.IsCountable() {
+		return NewError(errorhandler.UncountableSize)
+	}
+	if IsInfinite(set) {
+		return NewError(errorhandler.InfiniteSize)
+	}
+
+	if interval, ok := set.(*IntInterval); ok {
+		dif := interval.Upper.Subtract(interval.Lower).(Realness)
+		ratio := dif.Divide(interval.Step)
+		if ratio.(Realness).Less(NewInt(IntZero)).Value {
+			return NewInt(big.NewInt(0))
+		}
+
+		var count *big.Int
+		switch ratio := ratio.(type) {
+		case *Int:
+			count = new(big.Int).Set(ratio.Value)
+		case *Quotient:
+			count = new(big.Int).Div(ratio.Value.Num(), ratio.Value.Denom())
+		default:
+			count, _ = ratio.(Realness).ToReal().Value.Int(nil)
+		}
+		return NewInt(count.Add(count, IntOne))
+	}
+	return NewInt(big.NewInt(int64(set.(CountableSet).Length())))
+}
+
+// sortBuiltin gives a sorted copy of an array or tuple, the argument itself
+// isn't changed
+func sortBuiltin(args ...Object) Object {
+	if len(args) != 1 {
+		return NewArgumentError(1, args)
+	}
+	switch arg := args[0].(type) {
+	case *Array:
+		data := append([]Object{}, arg.Data...)
+		SortElements(data)
+		return &Array{Data: data}
+	case *List:
+		data := append([]Object{}, arg.Data...)
+		SortElements(data)
+		return &List{Data: data, IsSet: arg.IsSet}
+	default:
+		return invalidArgument(arg)
+	}
+}
+
+// printErrorBuiltin writes its arguments to the standard error like 'xuất'
+// does to the standard output
+func printErrorBuiltin(out Output, args ...Object) Object {
+	texts := []string{}
+	for _, arg := range args {
+		if str, ok := arg.(*String); ok {
+			texts = append(texts, str.Value)
+		} else {
+			texts = append(texts, arg.Display())
+		}
+	}
+	fmt.Fprintln(out.Stderr, strings.Join(texts, " "))
+	return NO_PRINT
+}
+
+// distanceBuiltin gives the Euclidean distance of two points written as
+// tuples: khoảng_cách({0, 0}, {3, 4}) = 5
+func distanceBuiltin(args ...Object) Object {
+	if len(args) != 2 {
+		return NewArgumentError(2, args)
+	}
+	p, ok := args[0].(*List)
+	if !ok {
+		return invalidArgument(args[0])
+	}
+	q, ok := args[1].(*List)
+	if !ok {
+		return invalidArgument(args[1])
+	}
+	if len(p.Data) != len(q.Data) {
+		return NewError(errorhandler.DimensionMismatch.With(len(p.Data), len(q.Data)))
+	}
+
+	sum := new(big.Float)
+	for i := range p.Data {
+		a, ok1 := p.Data[i].(Realness)
+		b, ok2 := q.Data[i].(Realness)
+		if !ok1 || !ok2 {
+			return NewError(errorhandler.RealCoordinates)
+		}
+		dif := new(big.Float).Sub(a.ToReal().Value, b.ToReal().Value)
+		sum.Add(sum, dif.Mul(dif, dif))
+	}
+	return NewReal(sum.Sqrt(sum))
+}
+
+func replaceBuiltin(args ...Object) Object {
+	if len(args) < 3 {
+		return NewArgumentError(3, args)
+	}
+	if len(args) > 4 {
+		return NewArgumentError(4, args)
+	}
+
+	strs, err := stringArguments(args[:3])
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 3 {
+		return &String{Value: strings.ReplaceAll(strs[0], strs[1], strs[2])}
+	}
+
+	count, ok := args[3].(*Int)
+	if !ok {
+		return invalidArgument(args[3])
+	}
+	if count.Value.Sign() < 0 {
+		return NewError(errorhandler.NegativeReplaceCount)
+	}
+	if count.Value.Cmp(big.NewInt(math.MaxInt)) > 0 {
+		return NewError(errorhandler.ReplaceCountTooLarge.With(math.MaxInt))
+	}
+	return &String{Value: strings.Replace(strs[0], strs[1], strs[2], int(count.Value.Int64()))}
+}
+
+func hasPrefixBuiltin(args ...Object) Object {
+	if len(args) != 2 {
+		return NewArgumentError(2, args)
+	}
+	strs, err := stringArguments(args)
+	if err != nil {
+		return err
+	}
+	return Condition(strings.HasPrefix(strs[0], strs[1]))
+}
+
+func hasSuffixBuiltin(args ...Object) Object {
+	if len(args) != 2 {
+		return NewArgumentError(2, args)
+	}
+	strs, err := stringArguments(args)
+	if err != nil {
+		return err
+	}
+	return Condition(strings.HasSuffix(strs[0], strs[1]))
+}
+
+// tabulateBuiltin gives the pairs {x, f(x)} for every x of a finite set
+func tabulateBuiltin(call CallFunction, args ...Object) Object {
+	if len(args) != 2 {
+		return NewArgumentError(2, args)
+	}
+	fn, ok := args[0].(*Function)
+	if !ok {
+		return invalidArgument(args[0])
+	}
+	set, err := finiteSetArgument(args[1])
+	if err != nil {
+		return err
+	}
+
+	table := &Array{Data: []Object{}}
+	set.Iterate(func(x Object) Object {
+		pair := &List{Data: []Object{x, call(fn, x)}}
+		table.Data = append(table.Data, pair)
+		return pair
+	})
+	return table
+}
+
+// mapBuiltin applies a function to every element and keeps the kind of the
+// collection: an array gives an array in the same order, a set gives a set so
+// equal images are kept once, e.g. ánh xạ(f, {1, 2, 3, 4}) with f(x) = x % 2
+// is {0, 1}
+func mapBuiltin(call CallFunction, args ...Object) Object {
+	if len(args) != 2 {
+		return NewArgumentError(2, args)
+	}
+	fn, ok := args[0].(*Function)
+	if !ok {
+		return invalidArgument(args[0])
+	}
+	set, err := finiteSetArgument(args[1])
+	if err != nil {
+		return err
+	}
+
+	images := []Object{}
+	_, isArray := set.(*Array)
+	set.Iterate(func(x Object) Object {
+		image := call(fn, x)
+		if isArray || !(&List{Data: images}).Contain(image).Value {
+			images = append(images, image)
+		}
+		return image
+	})
+
+	if isArray {
+		return &Array{Data: images}
+	}
+	SortElements(images)
+	return &List{Data: images, IsSet: true}
+}
+
+// groupBuiltin puts the elements with the same image into one array, in the
+// order they come, e.g. gom_nhóm(f, [1, 2, 3, 4]) with f(x) = x % 2 is
+// {1: [1, 3], 0: [2, 4]}
+func groupBuiltin(call CallFunction, args ...Object) Object {
+	if len(args) != 2 {
+		return NewArgumentError(2, args)
+	}
+	fn, ok := args[0].(*Function)
+	if !ok {
+		return invalidArgument(args[0])
+	}
+	set, err := finiteSetArgument(args[1])
+	if err != nil {
+		return err
+	}
+
+	groups := NewMap()
+	set.Iterate(func(x Object) Object {
+		image := call(fn, x)
+		key, ok := AsHashable(image)
+		if !ok {
+			if err == nil {
+				err = NewError(errorhandler.InvalidMapKey.With(image.Type()))
+			}
+			return image
+		}
+
+		if group, ok := groups.Get(key); ok {
+			group.(*Array).Data = append(group.(*Array).Data, x)
+		} else {
+			groups.Set(key, &Array{Data: []Object{x}})
+		}
+		return image
+	})
+
+	if err != nil {
+		return err
+	}
+	return groups
+}
+
+// zipBuiltin pairs the elements at the same position of two collections and
+// stops at the shorter one: ghép_cặp([1, 2, 3], ["a", "b", "c", "d"]) is
+// [{1, "a"}, {2, "b"}, {3, "c"}]. One of them can be infinite like
+// ghép_cặp(xs, [1..])
+func zipBuiltin(args ...Object) Object {
+	if len(args) != 2 {
+		return NewArgumentError(2, args)
+	}
+	sets := make([]CountableSet, 2)
+	for ind, arg := range args {
+		set, ok := arg.(CountableSet)
+		if !ok || !set.IsCountable() {
+			return invalidArgument(arg)
+		}
+		sets[ind] = set
+	}
+	if IsInfinite(sets[0]) && IsInfinite(sets[1]) {
+		return NewError(errorhandler.CannotZipInfinite)
+	}
+
+	// both are read in growing chunks, so an infinite one is only read as far
+	// as the other goes even when it can't be told infinite beforehand
+	for limit := 16; ; limit *= 2 {
+		left, right := take(sets[0], limit), take(sets[1], limit)
+		if len(left) == limit && len(right) == limit {
+			continue
+		}
+
+		pairs := &Array{Data: []Object{}}
+		for i := 0; i < len(left) && i < len(right); i++ {
+			pairs.Data = append(pairs.Data, &List{Data: []Object{left[i], right[i]}})
+		}
+		return pairs
+	}
+}
+
+// take reads the first limit elements of a set
+func take(set CountableSet, limit int) []Object {
+	data := []Object{}
+	stop := &LoopControl{Kind: BREAK_OBJ}
+	set.Iterate(func(x Object) Object {
+		data = append(data, x)
+		if len(data) == limit {
+			return stop
+		}
+		return x
+	})
+	return data
+}
+
+func finiteSetArgument(arg Object) (CountableSet, *Error) {
+	set, ok := arg.(CountableSet)
+	if !ok || !set.IsCountable() {
+		return nil, invalidArgument(arg)
+	}
+	if IsInfinite(set) {
+		return nil, NewError(errorhandler.InfiniteArgument)
+	}
+	return set, nil
+}
+
+func stringArguments(args []Object) ([]string, *Error) {
+	strs := []string{}
+	for _, arg := range args {
+		str, ok := arg.(*String)
+		if !ok {
+			return nil, invalidArgument(arg)
+		}
+		strs = append(strs, str.Value)
+	}
+	return strs, nil
+}
+
+func invalidArgument(arg Object) *Error {
+	errMsg := errorhandler.InvalidArgument.With(arg.Type())
+	return NewError(errMsg)
+}
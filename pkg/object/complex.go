@@ -20,40 +20,48 @@ type Complex struct {
 
 func (c *Complex) Type() ObjectType { return ComplexObj }
 func (c *Complex) Display() string {
-	s := ""
-	real, _ := c.Real.ToReal().Value.Float32()
-	imagine, _ := c.Imagine.ToReal().Value.Float32()
+	real := c.Real.ToReal().Value.Sign()
+	imagine := c.Imagine.ToReal().Value.Sign()
 
-	if real != 0 {
-		s += c.Real.Display()
-	}
-	if real != 0 && imagine != 0 {
-		if c.Imagine.ToReal().Value.Cmp(RealZero) == -1 {
-			s += " - "
-		} else {
-			s += " + "
+	if imagine == 0 {
+		if real == 0 {
+			return "0"
 		}
+		return c.Real.Display()
 	}
-	if imagine != 0 {
-		if imagine < 0 && real == 0 {
-			s += "-"
-			if imagine != -1 {
-				s += NewInt(big.NewInt(-1)).Multiply(c.Imagine).Display()
-			}
-		}
-		if imagine != 1 && imagine > 0 {
-			s += c.Imagine.Display()
-		}
-		if imagine != -1 && imagine < 0 {
-			s += NewInt(big.NewInt(-1)).Multiply(c.Imagine).Display()
-		}
-		s += "i"
+
+	s := ""
+	if real != 0 {
+		s = c.Real.Display()
 	}
-	if real == 0 && imagine == 0 {
-		s = "0"
+	switch {
+	case real != 0 && imagine < 0:
+		s += " - "
+	case real != 0:
+		s += " + "
+	case imagine < 0:
+		s += "-"
 	}
 
-	return s
+	// the sign is already written, 1 is left out like in 2 - i
+	coefficient := c.Imagine
+	if imagine < 0 {
+		coefficient = NewInt(big.NewInt(-1)).Multiply(c.Imagine).(Realness)
+	}
+	if !coefficient.Equal(NewInt(big.NewInt(1))).Value {
+		s += coefficient.Display()
+	}
+	return s + "i"
+}
+func (c *Complex) Equal(right Object) *Boolean {
+	switch right := right.(type) {
+	case Realness:
+		return Condition(c.Imagine.ToReal().IsZero() && c.Real.Equal(right).Value)
+	case *Complex:
+		return Condition(c.Real.Equal(right.Real).Value && c.Imagine.Equal(right.Imagine).Value)
+	default:
+		return INCOMPARABLE
+	}
 }
 func (c *Complex) ModuleSquare() Realness {
 	a := c.Real.Multiply(c.Real).(Realness)
@@ -128,6 +136,9 @@ func (c *Complex) Multiply(right Object) Object {
 func (c *Complex) Divide(right Object) Object {
 	switch right := right.(type) {
 	case Realness:
+		if right.ToReal().IsZero() {
+			return ZERO_DIVISION
+		}
 		real := c.Real.Divide(right).(Realness)
 		imagine := c.Imagine.Divide(right).(Realness)
 		return NewComplex(real, imagine)
@@ -0,0 +1,72 @@
+package object
+
+// DeepCopy copies arrays, sets and maps together with everything inside them,
+// other objects are returned as they are. A structure containing itself is
+// copied into a structure containing the copy, so cycles don't recurse forever.
+func DeepCopy(obj Object) Object {
+	return deepCopy(obj, map[Object]Object{})
+}
+
+func deepCopy(obj Object, copied map[Object]Object) Object {
+	if cp, ok := copied[obj]; ok {
+		return cp
+	}
+
+	switch obj := obj.(type) {
+	case *Array:
+		cp := &Array{Data: make([]Object, len(obj.Data))}
+		copied[obj] = cp
+		for ind, each := range obj.Data {
+			cp.Data[ind] = deepCopy(each, copied)
+		}
+		return cp
+
+	case *List:
+		cp := &List{Data: make([]Object, len(obj.Data)), IsSet: obj.IsSet}
+		copied[obj] = cp
+		for ind, each := range obj.Data {
+			cp.Data[ind] = deepCopy(each, copied)
+		}
+		return cp
+
+	case *Map:
+		cp := NewMap()
+		copied[obj] = cp
+		for _, key := range obj.Keys {
+			pair := obj.Pairs[key]
+			cp.Set(pair.Key.(Hashable), deepCopy(pair.Value, copied))
+		}
+		return cp
+
+	case *UnionSet:
+		cp := &UnionSet{}
+		copied[obj] = cp
+		cp.Left = deepCopy(obj.Left, copied).(Set)
+		cp.Right = deepCopy(obj.Right, copied).(Set)
+		return cp
+
+	case *IntersectionSet:
+		cp := &IntersectionSet{}
+		copied[obj] = cp
+		cp.Left = deepCopy(obj.Left, copied).(Set)
+		cp.Right = deepCopy(obj.Right, copied).(Set)
+		return cp
+
+	case *DiffSet:
+		cp := &DiffSet{}
+		copied[obj] = cp
+		cp.Left = deepCopy(obj.Left, copied).(Set)
+		cp.Right = deepCopy(obj.Right, copied).(Set)
+		return cp
+
+	case *ProductSet:
+		cp := &ProductSet{Sets: make([]Set, len(obj.Sets))}
+		copied[obj] = cp
+		for ind, each := range obj.Sets {
+			cp.Sets[ind] = deepCopy(each, copied).(Set)
+		}
+		return cp
+	}
+
+	return obj
+}
@@ -0,0 +1,46 @@
+package object
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestDeepCopy(t *testing.T) {
+	inner := &Array{Data: []Object{NewInt(big.NewInt(1)), NewInt(big.NewInt(2))}}
+	set := &List{Data: []Object{inner}}
+	source := &Array{Data: []Object{inner, set}}
+
+	cp := DeepCopy(source).(*Array)
+	if cp == source || cp.Display() != source.Display() {
+		t.Fatalf("wrong copy. want=%s, got=%s", source.Display(), cp.Display())
+	}
+
+	cp.Data[0].(*Array).Data[0] = NewInt(big.NewInt(100))
+	if source.Display() != "[[1, 2], {[1, 2]}]" {
+		t.Errorf("source changed after mutating the copy. got=%s", source.Display())
+	}
+	// shared elements stay shared in the copy
+	if cp.Display() != "[[100, 2], {[100, 2]}]" {
+		t.Errorf("copy has wrong value. got=%s", cp.Display())
+	}
+}
+
+func TestDeepCopyCycle(t *testing.T) {
+	source := &Array{Data: []Object{NewInt(big.NewInt(1))}}
+	source.Data = append(source.Data, source)
+
+	cp := DeepCopy(source).(*Array)
+	if cp == source {
+		t.Fatalf("copy is the source itself")
+	}
+	if cp.Data[1] != cp {
+		t.Errorf("cycle should point to the copy")
+	}
+}
+
+func TestDeepCopyPrimitive(t *testing.T) {
+	str := &String{Value: "abc"}
+	if DeepCopy(str) != str {
+		t.Errorf("immutable objects should be returned as they are")
+	}
+}
@@ -0,0 +1,39 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// DisplayLimit is the number of characters Display writes before cutting the
+// output with a note of what's left, 0 means no limit
+var DisplayLimit = 10_000
+
+// displayElements writes data between the brackets open and close, the
+// elements that don't fit in DisplayLimit are only counted
+func displayElements(open, close string, data []Object) string {
+	var out bytes.Buffer
+	out.WriteString(open)
+
+	for ind, each := range data {
+		if DisplayLimit > 0 && out.Len() >= DisplayLimit {
+			fmt.Fprintf(&out, "... (còn %d phần tử)", len(data)-ind)
+			break
+		}
+		out.WriteString(each.Display())
+		if ind != len(data)-1 {
+			out.WriteString(", ")
+		}
+	}
+	out.WriteString(close)
+
+	return out.String()
+}
+
+// displayDigits cuts the digits of a huge number to DisplayLimit
+func displayDigits(digits string) string {
+	if DisplayLimit <= 0 || len(digits) <= DisplayLimit {
+		return digits
+	}
+	return fmt.Sprintf("%s... (còn %d chữ số)", digits[:DisplayLimit], len(digits)-DisplayLimit)
+}
@@ -0,0 +1,58 @@
+package object
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestDisplayLargeArray(t *testing.T) {
+	data := make([]Object, 1_000_000)
+	for i := range data {
+		data[i] = NewInt(big.NewInt(int64(i)))
+	}
+
+	display := (&Array{Data: data}).Display()
+	if len(display) > DisplayLimit+100 {
+		t.Fatalf("display is too long, got %d characters", len(display))
+	}
+	if !strings.HasPrefix(display, "[0, 1, 2, ") || !strings.HasSuffix(display, " phần tử)]") {
+		t.Errorf("wrong display. got=%s...%s", display[:20], display[len(display)-30:])
+	}
+
+	small := &List{Data: data[:3]}
+	if small.Display() != "{0, 1, 2}" {
+		t.Errorf("small set shouldn't be cut. got=%s", small.Display())
+	}
+}
+
+func TestDisplayLimit(t *testing.T) {
+	defer func(limit int) { DisplayLimit = limit }(DisplayLimit)
+	DisplayLimit = 10
+
+	data := []Object{}
+	for i := 0; i < 10; i++ {
+		data = append(data, NewInt(big.NewInt(int64(i))))
+	}
+	tests := []struct {
+		obj      Object
+		expected string
+	}{
+		{&Array{Data: data}, "[0, 1, 2, ... (còn 7 phần tử)]"},
+		{&List{Data: data[:2]}, "{0, 1}"},
+		{NewInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil)), "1000000000... (còn 21 chữ số)"},
+		{NewInt(big.NewInt(-1234567890)), "-123456789... (còn 1 chữ số)"},
+		{NewInt(big.NewInt(1234567890)), "1234567890"},
+	}
+
+	for _, test := range tests {
+		if test.obj.Display() != test.expected {
+			t.Errorf("wrong display. want=%s, got=%s", test.expected, test.obj.Display())
+		}
+	}
+
+	DisplayLimit = 0
+	if (&Array{Data: data}).Display() != "[0, 1, 2, 3, 4, 5, 6, 7, 8, 9]" {
+		t.Errorf("display shouldn't be cut without limit. got=%s", (&Array{Data: data}).Display())
+	}
+}
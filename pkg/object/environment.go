@@ -16,19 +16,20 @@ type Environment struct {
 	outer *Environment
 }
 
+// Get looks the name up from the innermost scope outwards, the builtins come
+// last so 'cho mã = 5' can hide the builtin mã
 func (e *Environment) Get(name string) (Object, bool) {
 	obj, ok := e.GetInScope(name)
 	if !ok && e.outer != nil {
-		obj, ok = e.outer.Get(name)
+		return e.outer.Get(name)
+	}
+	if !ok {
+		obj, ok = Builtins[name]
 	}
 	return obj, ok
 }
 
 func (e *Environment) GetInScope(name string) (Object, bool) {
-	if obj, ok := Builtins[name]; ok {
-		return obj, ok
-	}
-
 	obj, ok := e.store[name]
 	return obj, ok
 }
@@ -2,13 +2,30 @@ package object
 
 import (
 	"bytes"
+	"io"
 	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
 )
 
 const (
 	FUNC_OBJ = "Hàm"
 )
 
+// CallFunction is given by the evaluator to builtins taking other functions
+// as arguments, e.g. bảng giá trị(f, A)
+type CallFunction func(fn *Function, args ...Object) Object
+
+// Output is given by the evaluator to builtins writing to the standard output
+// or standard error of the session, e.g. in_lỗi
+type Output struct {
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// EvalSource is given by the evaluator to builtins running a program from a
+// string with the settings of the session, e.g. thay thế
+type EvalSource func(input string, env *Environment) (Object, *errorhandler.ErrorList)
+
 type Function struct {
 	Ident  *ast.Identifier
 	Params []*ast.Identifier
@@ -16,12 +33,27 @@ type Function struct {
 	Env    *Environment
 
 	Builtin     func(args ...Object) Object
+	HigherOrder func(call CallFunction, args ...Object) Object
+	Writer      func(out Output, args ...Object) Object
+	Evaluating  func(eval EvalSource, args ...Object) Object
 	LeftCompose *Function
 }
 
 func (fn *Function) Type() ObjectType { return FUNC_OBJ }
+func (fn *Function) IsBuiltin() bool {
+	return fn.Builtin != nil || fn.HigherOrder != nil || fn.Writer != nil || fn.Evaluating != nil
+}
+
+// Name is the declared name of a function, functions written as hàm(x) = ...
+// don't have one
+func (fn *Function) Name() string {
+	if fn.Ident == nil {
+		return "hàm"
+	}
+	return fn.Ident.Value
+}
 func (fn *Function) Display() string {
-	if fn.Builtin != nil {
+	if fn.IsBuiltin() {
 		return "<Hàm cài đặt sẵn>"
 	}
 
@@ -31,12 +63,12 @@ func (fn *Function) Display() string {
 		out.WriteString("(")
 	}
 
-	out.WriteString(fn.Ident.Value)
+	out.WriteString(fn.Name())
 
 	tempFn := fn
 	for tempFn.LeftCompose != nil {
 		tempFn = tempFn.LeftCompose
-		out.WriteString("." + tempFn.Ident.Value)
+		out.WriteString("." + tempFn.Name())
 	}
 
 	if fn.LeftCompose != nil {
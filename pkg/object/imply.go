@@ -1,11 +1,16 @@
 package object
 
+import "vanvo/pkg/ast"
+
 const (
 	IMPLY_OBJ = "Giá trị trả về"
 )
 
+// Imply is the value of 'trả về', it leaves every block until it reaches the
+// function being called
 type Imply struct {
 	Value Object
+	Node  ast.Node
 }
 
 func (r *Imply) Type() ObjectType { return IMPLY_OBJ }
@@ -0,0 +1,28 @@
+package object
+
+import "vanvo/pkg/ast"
+
+const (
+	BREAK_OBJ    = "Dừng vòng lặp"
+	CONTINUE_OBJ = "Tiếp vòng lặp"
+)
+
+// LoopControl is the value of 'dừng' and 'tiếp', it leaves every block until
+// it reaches the innermost loop, or the loop named Label when it has one
+type LoopControl struct {
+	Kind  ObjectType
+	Node  ast.Node
+	Label string
+}
+
+func (lc *LoopControl) Type() ObjectType { return lc.Kind }
+func (lc *LoopControl) Display() string  { return "" }
+
+// StopsIteration tells if a value returned to Iterate should end the iteration,
+// a labeled 'tiếp' also does when it is meant for an outer loop
+func StopsIteration(obj Object) bool {
+	if control, ok := obj.(*LoopControl); ok && control.Label != "" {
+		return true
+	}
+	return obj.Type() == IMPLY_OBJ || obj.Type() == BREAK_OBJ
+}
@@ -0,0 +1,176 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+const (
+	MapObj = "Từ Điển"
+)
+
+// numberKey is shared by every kind of number, so 2, 2.0 and 4/2 are the
+// same key just like they are equal
+const numberKey = "Số"
+
+// tupleKey keeps a tuple from being the same key as the set of its elements
+const tupleKey = "Bộ"
+
+type HashKey struct {
+	Type  ObjectType
+	Value string
+}
+
+// Hashable is implemented by the values that can be keys of a Map
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+func ratKey(value *big.Rat) HashKey {
+	return HashKey{Type: numberKey, Value: value.RatString()}
+}
+
+func (i *Int) HashKey() HashKey {
+	return ratKey(new(big.Rat).SetInt(i.Value))
+}
+
+// HashKey of an infinity is its own, it has no fraction to share with other
+// numbers
+func (re *Real) HashKey() HashKey {
+	if re.Value.IsInf() {
+		return HashKey{Type: numberKey, Value: re.Value.String()}
+	}
+	value, _ := re.Value.Rat(nil)
+	return ratKey(value)
+}
+func (q *Quotient) HashKey() HashKey {
+	return ratKey(q.Value)
+}
+func (s *String) HashKey() HashKey {
+	return HashKey{Type: StringObj, Value: s.Value}
+}
+func (b *Boolean) HashKey() HashKey {
+	return HashKey{Type: BoolObj, Value: b.Display()}
+}
+
+// HashKey of a tuple is made from the keys of its elements in order. A set
+// sorts them instead, so the order they were added in doesn't matter and
+// repeated elements count once
+func (list *List) HashKey() HashKey {
+	keys := []string{}
+	seen := map[HashKey]bool{}
+	for _, element := range list.Data {
+		key := HashKey{Type: element.Type(), Value: element.Display()}
+		if element, ok := element.(Hashable); ok {
+			key = element.HashKey()
+		}
+		if !list.IsSet || !seen[key] {
+			seen[key] = true
+			keys = append(keys, fmt.Sprintf("%s:%q", key.Type, key.Value))
+		}
+	}
+	if !list.IsSet {
+		return HashKey{Type: tupleKey, Value: strings.Join(keys, ",")}
+	}
+	sort.Strings(keys)
+	return HashKey{Type: SetObj, Value: strings.Join(keys, ",")}
+}
+
+// AsHashable gives obj as a key of a Map, a set is a key only when all of its
+// elements are
+func AsHashable(obj Object) (Hashable, bool) {
+	if list, ok := obj.(*List); ok {
+		for _, element := range list.Data {
+			if _, ok := AsHashable(element); !ok {
+				return nil, false
+			}
+		}
+	}
+	key, ok := obj.(Hashable)
+	return key, ok
+}
+
+type MapPair struct {
+	Key   Object
+	Value Object
+}
+
+// Map keeps its pairs in the order their keys are first added, setting a key
+// again only replaces its value. A set key is copied so changing the set later
+// doesn't change the key
+type Map struct {
+	Pairs map[HashKey]*MapPair
+	Keys  []HashKey
+}
+
+func NewMap() *Map {
+	return &Map{Pairs: map[HashKey]*MapPair{}}
+}
+
+func (m *Map) Type() ObjectType { return MapObj }
+func (m *Map) Display() string {
+	var out bytes.Buffer
+	out.WriteString("{")
+
+	for ind, key := range m.Keys {
+		if DisplayLimit > 0 && out.Len() >= DisplayLimit {
+			fmt.Fprintf(&out, "... (còn %d phần tử)", len(m.Keys)-ind)
+			break
+		}
+		pair := m.Pairs[key]
+		out.WriteString(pair.Key.Display() + ": " + pair.Value.Display())
+		if ind != len(m.Keys)-1 {
+			out.WriteString(", ")
+		}
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+func (m *Map) Length() int {
+	return len(m.Keys)
+}
+
+// Contain tells if obj is a key of the map: 3 thuộc m
+func (m *Map) Contain(obj Object) *Boolean {
+	if key, ok := AsHashable(obj); ok {
+		if _, ok := m.Pairs[key.HashKey()]; ok {
+			return TRUE
+		}
+	}
+	return FALSE
+}
+
+// Iterate gives the keys in the order they were added
+func (m *Map) Iterate(callback IterateCallback) {
+	for _, hash := range m.Keys {
+		if StopsIteration(callback(m.Pairs[hash].Key)) {
+			break
+		}
+	}
+}
+func (m *Map) Get(key Hashable) (Object, bool) {
+	pair, ok := m.Pairs[key.HashKey()]
+	if !ok {
+		return nil, false
+	}
+	return pair.Value, true
+}
+func (m *Map) Set(key Hashable, value Object) Object {
+	hash := key.HashKey()
+	if pair, ok := m.Pairs[hash]; ok {
+		pair.Value = value
+		return value
+	}
+	var keyObj Object = key
+	if list, ok := key.(*List); ok {
+		keyObj = DeepCopy(list)
+	}
+	m.Pairs[hash] = &MapPair{Key: keyObj, Value: value}
+	m.Keys = append(m.Keys, hash)
+	return value
+}
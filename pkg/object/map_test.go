@@ -0,0 +1,69 @@
+package object
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSetHashKey(t *testing.T) {
+	one, two := NewInt(big.NewInt(1)), NewInt(big.NewInt(2))
+	a := &List{Data: []Object{one, two, &String{Value: "x"}}, IsSet: true}
+	b := &List{Data: []Object{&String{Value: "x"}, two, one}, IsSet: true}
+
+	if a.HashKey() != b.HashKey() {
+		t.Errorf("sets with the same elements have different keys: %v and %v", a.HashKey(), b.HashKey())
+	}
+	if !a.Equal(b).Value || !b.Equal(a).Value {
+		t.Errorf("%s should equal %s", a.Display(), b.Display())
+	}
+
+	nested := &List{Data: []Object{a, one}, IsSet: true}
+	reordered := &List{Data: []Object{one, b}, IsSet: true}
+	if nested.HashKey() != reordered.HashKey() || !nested.Equal(reordered).Value {
+		t.Errorf("%s should be the same key as %s", nested.Display(), reordered.Display())
+	}
+
+	repeated := &List{Data: []Object{one, one, two}, IsSet: true}
+	if repeated.HashKey() != (&List{Data: []Object{two, one}, IsSet: true}).HashKey() {
+		t.Errorf("repeated elements should count once in %s", repeated.Display())
+	}
+
+	// "a,b" must not collide with "a" and "b"
+	joined := &List{Data: []Object{&String{Value: "a\",Chuỗi:\"b"}}}
+	split := &List{Data: []Object{&String{Value: "a"}, &String{Value: "b"}}}
+	if joined.HashKey() == split.HashKey() {
+		t.Errorf("%s and %s should have different keys", joined.Display(), split.Display())
+	}
+
+	if _, ok := AsHashable(&List{Data: []Object{&Array{}}}); ok {
+		t.Errorf("a set of arrays shouldn't be a key")
+	}
+}
+
+// tuples like points keep their order, {1, 2} and {2, 1} are different
+func TestTupleHashKey(t *testing.T) {
+	one, two := NewInt(big.NewInt(1)), NewInt(big.NewInt(2))
+	a := &List{Data: []Object{one, two}}
+	b := &List{Data: []Object{two, one}}
+
+	if a.HashKey() == b.HashKey() {
+		t.Errorf("%s and %s should have different keys", a.Display(), b.Display())
+	}
+	if a.Equal(b).Value || b.Equal(a).Value {
+		t.Errorf("%s shouldn't equal %s", a.Display(), b.Display())
+	}
+	if !a.Equal(&List{Data: []Object{one, two}}).Value {
+		t.Errorf("%s should equal itself", a.Display())
+	}
+
+	repeated := &List{Data: []Object{one, one}}
+	if repeated.Equal(&List{Data: []Object{one}}).Value {
+		t.Errorf("%s shouldn't equal {1}", repeated.Display())
+	}
+
+	// a tuple is compared as a set when the other side is one
+	set := &List{Data: []Object{one, two}, IsSet: true}
+	if !b.Equal(set).Value || !set.Equal(b).Value {
+		t.Errorf("%s should equal the set %s", b.Display(), set.Display())
+	}
+}
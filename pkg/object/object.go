@@ -2,6 +2,9 @@ package object
 
 type ObjectType string
 
+// Term lets messages translate the name of the type
+func (t ObjectType) Term() string { return string(t) }
+
 type Object interface {
 	Type() ObjectType
 	Display() string
@@ -35,6 +35,15 @@ type Exponential interface {
 	Power(Object) Object
 }
 
+type Bitwise interface {
+	Object
+	BitAnd(Object) Object
+	BitOr(Object) Object
+	BitXor(Object) Object
+	ShiftLeft(Object) Object
+	ShiftRight(Object) Object
+}
+
 type Equal interface {
 	Object
 	Equal(Object) *Boolean
@@ -16,6 +16,7 @@ const (
 	NullObj         = "Rỗng"
 	IncomparableObj = "Không thể so sánh được"
 	CantOperateObj  = "Không thể thực hiện phép tính"
+	NotRealObj      = "Không phải số thực"
 )
 
 var (
@@ -26,6 +27,13 @@ var (
 	ZERO_DIVISION = &Null{}
 	CANT_OPERATE  = &CantOperate{}
 
+	// NOT_REAL is the value of a power that isn't a real number: (-8)^(1/3).
+	// It has its own type since every empty struct may share one address
+	NOT_REAL = &NotReal{}
+
+	// NO_PRINT is the value of statements that show nothing in the REPL
+	NO_PRINT = &Null{}
+
 	IntZero  = big.NewInt(0)
 	IntOne   = big.NewInt(1)
 	RealZero = big.NewFloat(0)
@@ -49,7 +57,7 @@ type Int struct {
 }
 
 func (i *Int) Type() ObjectType { return IntObj }
-func (i *Int) Display() string  { return fmt.Sprint(i.Value) }
+func (i *Int) Display() string  { return displayDigits(i.Value.String()) }
 func (i *Int) ToReal() *Real {
 	return &Real{Value: new(big.Float).SetInt(i.Value)}
 }
@@ -109,10 +117,10 @@ func (i *Int) Multiply(right Object) Object {
 func (i *Int) Divide(right Object) Object {
 	switch right := right.(type) {
 	case *Int:
-		if right.Value.Int64() == 0 {
+		if right.Value.Sign() == 0 {
 			return ZERO_DIVISION
 		}
-		return NewQuotient(i.Value, right.Value)
+		return rational(new(big.Rat).SetFrac(i.Value, right.Value))
 	case *Real:
 		rightVal, _ := right.Value.Float64()
 		if rightVal == 0 {
@@ -128,30 +136,73 @@ func (i *Int) Divide(right Object) Object {
 		return CANT_OPERATE
 	}
 }
+
+// Mod is the Euclidean remainder, it's never negative whatever the signs of
+// the operands are: -7 % 3 = 2, 7 % -3 = 1. Reals and quotients follow the
+// same rule: 5.5 % 2 = 1.5, -1/2 % 1 = 1/2
 func (i *Int) Mod(right Object) Object {
 	switch right := right.(type) {
 	case *Int:
-		if right.Value.Int64() == 0 {
+		if right.Value.Sign() == 0 {
 			return ZERO_DIVISION
 		}
 		return NewInt(new(big.Int).Mod(i.Value, right.Value))
+	case *Real:
+		return i.ToReal().Mod(right)
+	case *Quotient:
+		return i.ToQuotient().Mod(right)
 	default:
 		return CANT_OPERATE
 	}
 }
+
+// Power keeps integers exact, a negative exponent gives a quotient: 2^-2 = 1/4
 func (i *Int) Power(right Object) Object {
 	switch right := right.(type) {
 	case *Int:
+		if right.Value.Sign() < 0 {
+			return i.ToQuotient().Power(right)
+		}
 		return NewInt(new(big.Int).Exp(i.Value, right.Value, nil))
 	case *Real:
 		intVal := new(big.Float).SetInt(i.Value)
-		return NewReal(bigfloat.Pow(intVal, right.Value))
+		return powReal(intVal, right.Value)
 	case *Quotient:
-		return NewReal(i.ToReal().Power(right.ToReal()).(*Real).Value)
+		return i.ToReal().Power(right.ToReal())
 	default:
 		return CANT_OPERATE
 	}
 }
+func (i *Int) BitAnd(right Object) Object {
+	if right, ok := right.(*Int); ok {
+		return NewInt(new(big.Int).And(i.Value, right.Value))
+	}
+	return CANT_OPERATE
+}
+func (i *Int) BitOr(right Object) Object {
+	if right, ok := right.(*Int); ok {
+		return NewInt(new(big.Int).Or(i.Value, right.Value))
+	}
+	return CANT_OPERATE
+}
+func (i *Int) BitXor(right Object) Object {
+	if right, ok := right.(*Int); ok {
+		return NewInt(new(big.Int).Xor(i.Value, right.Value))
+	}
+	return CANT_OPERATE
+}
+func (i *Int) ShiftLeft(right Object) Object {
+	if right, ok := right.(*Int); ok && right.Value.IsUint64() {
+		return NewInt(new(big.Int).Lsh(i.Value, uint(right.Value.Uint64())))
+	}
+	return CANT_OPERATE
+}
+func (i *Int) ShiftRight(right Object) Object {
+	if right, ok := right.(*Int); ok && right.Value.IsUint64() {
+		return NewInt(new(big.Int).Rsh(i.Value, uint(right.Value.Uint64())))
+	}
+	return CANT_OPERATE
+}
 func (i *Int) Equal(right Object) *Boolean {
 	switch right := right.(type) {
 	case *Int:
@@ -272,19 +323,61 @@ func (r *Real) Divide(right Object) Object {
 		return CANT_OPERATE
 	}
 }
+func (r *Real) Mod(right Object) Object {
+	switch right := right.(type) {
+	case *Int:
+		return r.Mod(right.ToReal())
+	case *Quotient:
+		return r.Mod(right.ToReal())
+	case *Real:
+		if right.Value.Sign() == 0 {
+			return ZERO_DIVISION
+		}
+		// the quotient of an infinity has no integer part
+		if r.Value.IsInf() || right.Value.IsInf() {
+			return CANT_OPERATE
+		}
+		divisor := new(big.Float).Abs(right.Value)
+		quo := new(big.Float).Quo(r.Value, divisor)
+		floor, _ := quo.Int(nil)
+		if quo.Sign() < 0 && !quo.IsInt() {
+			floor.Sub(floor, IntOne)
+		}
+		multiple := new(big.Float).Mul(divisor, new(big.Float).SetInt(floor))
+		return NewReal(new(big.Float).Sub(r.Value, multiple))
+	default:
+		return CANT_OPERATE
+	}
+}
 func (r *Real) Power(right Object) Object {
 	switch right := right.(type) {
 	case *Int:
 		intVal := new(big.Float).SetInt(right.Value)
-		return NewReal(bigfloat.Pow(r.Value, intVal))
+		return powReal(r.Value, intVal)
 	case *Real:
-		return NewReal(bigfloat.Pow(r.Value, right.Value))
+		return powReal(r.Value, right.Value)
 	case *Quotient:
-		return NewReal(bigfloat.Pow(r.Value, right.ToReal().Value))
+		return powReal(r.Value, right.ToReal().Value)
 	default:
 		return CANT_OPERATE
 	}
 }
+
+// powReal is bigfloat.Pow for any base, a negative base only has a real
+// power when the exponent is an integer, otherwise it gives NOT_REAL
+func powReal(base, exp *big.Float) Object {
+	if base.Sign() >= 0 {
+		return NewReal(bigfloat.Pow(base, exp))
+	}
+	if !exp.IsInt() {
+		return NOT_REAL
+	}
+	result := bigfloat.Pow(new(big.Float).Neg(base), exp)
+	if odd, _ := exp.Int(nil); odd.Bit(0) == 1 {
+		result.Neg(result)
+	}
+	return NewReal(result)
+}
 func (r *Real) Sqrt() Number {
 	val := r.Value
 	if val.Cmp(RealZero) == -1 {
@@ -380,6 +473,15 @@ func NewQuotient(numer, denom *big.Int) *Quotient {
 	return q
 }
 
+// rational gives the result of an exact operation, it's an integer whenever
+// the value is a whole number: 6/3 = 2, 1/3 * 3 = 1
+func rational(value *big.Rat) Number {
+	if value.IsInt() {
+		return NewInt(new(big.Int).Set(value.Num()))
+	}
+	return &Quotient{Value: value}
+}
+
 type Quotient struct {
 	Value *big.Rat
 }
@@ -398,6 +500,24 @@ func (q *Quotient) ToReal() *Real {
 func (q *Quotient) ToComplex() *Complex {
 	return NewComplex(q, NewInt(IntZero))
 }
+
+// IsRepeatingDecimal checks if the decimal form never ends, which is when
+// the denominator has a prime factor other than 2 and 5
+func (q *Quotient) IsRepeatingDecimal() bool {
+	denom := new(big.Int).Set(q.Value.Denom())
+	for _, factor := range []int64{2, 5} {
+		f := big.NewInt(factor)
+		mod := new(big.Int)
+		for {
+			quo, rem := new(big.Int).QuoRem(denom, f, mod)
+			if rem.Sign() != 0 {
+				break
+			}
+			denom = quo
+		}
+	}
+	return denom.Cmp(IntOne) != 0
+}
 func (q *Quotient) Inverse() *Quotient {
 	return &Quotient{Value: new(big.Rat).Inv(q.Value)}
 }
@@ -408,7 +528,7 @@ func (q *Quotient) Add(right Object) Object {
 	case *Real:
 		return q.ToReal().Add(right)
 	case *Quotient:
-		return &Quotient{Value: new(big.Rat).Add(q.Value, right.Value)}
+		return rational(new(big.Rat).Add(q.Value, right.Value))
 	case *Complex:
 		return right.Add(q)
 	default:
@@ -422,7 +542,7 @@ func (q *Quotient) Subtract(right Object) Object {
 	case *Real:
 		return q.ToReal().Subtract(right)
 	case *Quotient:
-		return &Quotient{Value: new(big.Rat).Sub(q.Value, right.Value)}
+		return rational(new(big.Rat).Sub(q.Value, right.Value))
 	case *Complex:
 		return q.ToComplex().Subtract(right)
 	default:
@@ -436,7 +556,7 @@ func (q *Quotient) Multiply(right Object) Object {
 	case *Real:
 		return q.ToReal().Multiply(right)
 	case *Quotient:
-		return &Quotient{Value: new(big.Rat).Mul(q.Value, right.Value)}
+		return rational(new(big.Rat).Mul(q.Value, right.Value))
 	case *Complex:
 		return right.Multiply(q)
 	default:
@@ -450,22 +570,48 @@ func (q *Quotient) Divide(right Object) Object {
 	case *Real:
 		return q.ToReal().Divide(right)
 	case *Quotient:
-		if right.Value.Denom().Cmp(IntZero) == 0 {
+		if right.Value.Sign() == 0 {
 			return ZERO_DIVISION
 		}
-		return &Quotient{Value: new(big.Rat).Quo(q.Value, right.Value)}
+		return rational(new(big.Rat).Quo(q.Value, right.Value))
 	case *Complex:
 		return q.ToComplex().Divide(right)
 	default:
 		return CANT_OPERATE
 	}
 }
+func (q *Quotient) Mod(right Object) Object {
+	switch right := right.(type) {
+	case *Int:
+		return q.Mod(right.ToQuotient())
+	case *Real:
+		return q.ToReal().Mod(right)
+	case *Quotient:
+		if right.Value.Sign() == 0 {
+			return ZERO_DIVISION
+		}
+		divisor := new(big.Rat).Abs(right.Value)
+		quo := new(big.Rat).Quo(q.Value, divisor)
+		floor := new(big.Int).Div(quo.Num(), quo.Denom())
+		multiple := new(big.Rat).Mul(divisor, new(big.Rat).SetInt(floor))
+		return rational(new(big.Rat).Sub(q.Value, multiple))
+	default:
+		return CANT_OPERATE
+	}
+}
 func (q *Quotient) Power(right Object) Object {
 	switch right := right.(type) {
 	case *Int:
-		numer := new(big.Int).Exp(q.Value.Num(), right.Value, nil)
-		denom := new(big.Int).Exp(q.Value.Denom(), right.Value, nil)
-		return NewQuotient(numer, denom)
+		exp := new(big.Int).Abs(right.Value)
+		numer := new(big.Int).Exp(q.Value.Num(), exp, nil)
+		denom := new(big.Int).Exp(q.Value.Denom(), exp, nil)
+		if right.Value.Sign() < 0 {
+			if numer.Sign() == 0 {
+				return ZERO_DIVISION
+			}
+			numer, denom = denom, numer
+		}
+		return rational(new(big.Rat).SetFrac(numer, denom))
 	case *Real:
 		return q.ToReal().Power(right)
 	case *Quotient:
@@ -544,6 +690,11 @@ type CantOperate struct{}
 func (*CantOperate) Type() ObjectType { return CantOperateObj }
 func (*CantOperate) Display() string  { return CantOperateObj }
 
+type NotReal struct{}
+
+func (*NotReal) Type() ObjectType { return NotRealObj }
+func (*NotReal) Display() string  { return NotRealObj }
+
 func Condition(condition bool) *Boolean {
 	if condition {
 		return TRUE
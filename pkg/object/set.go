@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"math"
 	"math/big"
+	"sort"
 	"vanvo/pkg/ast"
 )
 
@@ -28,6 +29,13 @@ type Indexable interface {
 	At(index int) Object
 }
 
+// Mutable is implemented by the values whose elements can be assigned:
+// xs[0] = 1. SetAt gives IndexError when the index is out of range
+type Mutable interface {
+	Object
+	SetAt(index int, value Object) Object
+}
+
 type CountableSet interface {
 	Set
 	Indexable
@@ -35,24 +43,70 @@ type CountableSet interface {
 	Iterate(IterateCallback)
 }
 
+// IsInfinite reports whether a set is known to have infinitely many elements,
+// lazy sets like list comprehensions can't be known without evaluating them.
+func IsInfinite(set Set) bool {
+	switch set := set.(type) {
+	case *RealInterval:
+		return true
+	case *IntInterval:
+		return set.Upper.ToReal().Value.IsInf()
+	case *UnionSet:
+		return IsInfinite(set.Left) || IsInfinite(set.Right)
+	case *IntersectionSet:
+		return IsInfinite(set.Left) && IsInfinite(set.Right)
+	case *DiffSet:
+		return IsInfinite(set.Left)
+	case *ProductSet:
+		for _, each := range set.Sets {
+			if IsInfinite(each) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SortElements orders the elements of a materialized set so it always displays
+// the same way: numbers go first in ascending order, then strings, the other
+// values keep their order
+func SortElements(data []Object) {
+	rank := func(obj Object) int {
+		switch obj.(type) {
+		case Realness:
+			return 0
+		case *String:
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(data, func(i, j int) bool {
+		a, b := data[i], data[j]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		switch a := a.(type) {
+		case Realness:
+			return a.Less(b).Value
+		case *String:
+			return a.Value < b.(*String).Value
+		}
+		return false
+	})
+}
+
+// List is a tuple like the point {1, 2}, or a set when it's made by a set
+// operation like a set builder or 'hợp'. Only sets ignore the order and
+// repetition of their elements
 type List struct {
-	Data []Object
+	Data  []Object
+	IsSet bool
 }
 
 func (list *List) Type() ObjectType { return SetObj }
 func (list *List) Display() string {
-	var out bytes.Buffer
-	out.WriteString("{")
-
-	for ind, each := range list.Data {
-		out.WriteString(each.Display())
-		if ind != len(list.Data)-1 {
-			out.WriteString(", ")
-		}
-	}
-	out.WriteString("}")
-
-	return out.String()
+	return displayElements("{", "}", list.Data)
 }
 func (list *List) IsCountable() bool { return true }
 func (list *List) Contain(obj Object) *Boolean {
@@ -65,19 +119,58 @@ func (list *List) Contain(obj Object) *Boolean {
 	}
 	return FALSE
 }
+
+// Equal compares tuples element by element, so {1, 2} != {2, 1}. When one
+// side is a set both are compared by their elements regardless of order
+func (list *List) Equal(right Object) *Boolean {
+	other, ok := right.(*List)
+	if !ok {
+		return INCOMPARABLE
+	}
+	if !list.IsSet && !other.IsSet {
+		if len(list.Data) != len(other.Data) {
+			return FALSE
+		}
+		for ind, each := range list.Data {
+			each, ok := each.(Equal)
+			if !ok || !each.Equal(other.Data[ind]).Value {
+				return FALSE
+			}
+		}
+		return TRUE
+	}
+	for _, each := range list.Data {
+		if !other.Contain(each).Value {
+			return FALSE
+		}
+	}
+	for _, each := range other.Data {
+		if !list.Contain(each).Value {
+			return FALSE
+		}
+	}
+	return TRUE
+}
 func (list *List) At(index int) Object {
-	if index >= len(list.Data) {
+	if index < 0 || index >= len(list.Data) {
 		return IndexError
 	}
 	return list.Data[index]
 }
+func (list *List) SetAt(index int, value Object) Object {
+	if index < 0 || index >= len(list.Data) {
+		return IndexError
+	}
+	list.Data[index] = value
+	return value
+}
 func (list *List) Length() int {
 	return len(list.Data)
 }
 func (list *List) Iterate(callback IterateCallback) {
 	for _, each := range list.Data {
 		val := callback(each)
-		if val.Type() == IMPLY_OBJ {
+		if StopsIteration(val) {
 			break
 		}
 	}
@@ -144,7 +237,7 @@ func (list *ListComprehension) Iterate(callback IterateCallback) {
 	data := list.At(0)
 	for i := 1; data != IndexError; i++ {
 		val := callback(data)
-		if val.Type() == IMPLY_OBJ {
+		if StopsIteration(val) {
 			return
 		}
 		data = list.At(i)
@@ -179,7 +272,7 @@ func (interval *IntInterval) Display() string {
 func (interval *IntInterval) IsCountable() bool { return true }
 func (interval *IntInterval) Contain(obj Object) *Boolean {
 	if obj, isReal := obj.(Realness); isReal {
-		if interval.Upper.Less(obj).Value {
+		if obj.Less(interval.Lower).Value || interval.Upper.Less(obj).Value {
 			return FALSE
 		}
 
@@ -203,13 +296,16 @@ func (interval *IntInterval) Iterate(callback IterateCallback) {
 	element := interval.Lower
 	for element.Less(interval.Upper) == TRUE || element.Equal(interval.Upper) == TRUE {
 		val := callback(element)
-		if val.Type() == IMPLY_OBJ {
+		if StopsIteration(val) {
 			break
 		}
 		element = element.Add(interval.Step).(Realness)
 	}
 }
 func (interval *IntInterval) At(index int) Object {
+	if index < 0 {
+		return IndexError
+	}
 	indexInt := NewInt(big.NewInt(int64(index)))
 	val := indexInt.Multiply(interval.Step).(Realness).Add(interval.Lower)
 
@@ -222,17 +318,29 @@ func (interval *IntInterval) At(index int) Object {
 type RealInterval struct {
 	Upper Realness
 	Lower Realness
+
+	// open bounds don't belong to the interval: (0, 1]
+	LowerOpen bool
+	UpperOpen bool
 }
 
 func (interval *RealInterval) IsCountable() bool { return false }
 func (interval *RealInterval) Type() ObjectType  { return SetObj }
 func (interval *RealInterval) Display() string {
 	var out bytes.Buffer
-	out.WriteString("[")
+	if interval.LowerOpen {
+		out.WriteString("(")
+	} else {
+		out.WriteString("[")
+	}
 	out.WriteString(interval.Lower.Display())
 	out.WriteString(",")
 	out.WriteString(interval.Upper.Display())
-	out.WriteString("]")
+	if interval.UpperOpen {
+		out.WriteString(")")
+	} else {
+		out.WriteString("]")
+	}
 
 	return out.String()
 }
@@ -240,8 +348,10 @@ func (interval *RealInterval) Contain(obj Object) *Boolean {
 	switch obj := obj.(type) {
 	case Realness:
 		real := obj.ToReal()
-		cond1 := real.Less(interval.Upper) == TRUE || real.Equal(interval.Upper) == TRUE
-		cond2 := interval.Lower.Less(real) == TRUE || interval.Lower.Equal(real) == TRUE
+		cond1 := real.Less(interval.Upper) == TRUE ||
+			!interval.UpperOpen && real.Equal(interval.Upper) == TRUE
+		cond2 := interval.Lower.Less(real) == TRUE ||
+			!interval.LowerOpen && interval.Lower.Equal(real) == TRUE
 		return Condition(cond1 && cond2)
 	default:
 		return FALSE
@@ -258,6 +368,24 @@ func (interval *RealInterval) Less(right Object) *Boolean {
 	}
 }
 
+// Integers gives the integers of an interval whose bounds are integers, so
+// x thuộc [1, 5] can go through 1 to 5 where x needs values
+func (interval *RealInterval) Integers() (*IntInterval, bool) {
+	lower, ok1 := interval.Lower.(*Int)
+	upper, ok2 := interval.Upper.(*Int)
+	if !ok1 || !ok2 {
+		return nil, false
+	}
+	result := &IntInterval{Lower: lower, Upper: upper, Step: NewInt(big.NewInt(1))}
+	if interval.LowerOpen {
+		result.Lower = NewInt(new(big.Int).Add(lower.Value, IntOne))
+	}
+	if interval.UpperOpen {
+		result.Upper = NewInt(new(big.Int).Sub(upper.Value, IntOne))
+	}
+	return result, true
+}
+
 type UnionSet struct {
 	Left  Set
 	Right Set
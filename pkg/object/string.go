@@ -15,9 +15,13 @@ type String struct {
 
 func (s *String) Type() ObjectType { return StringObj }
 func (s *String) Display() string  { return fmt.Sprintf("\"%s\"", s.Value) }
+func (s *String) Length() int {
+	return len([]rune(s.Value))
+}
 func (s *String) At(index int) Object {
-	if index < len(s.Value) {
-		return &String{Value: string(s.Value[index])}
+	runes := []rune(s.Value)
+	if 0 <= index && index < len(runes) {
+		return &String{Value: string(runes[index])}
 	}
 	return IndexError
 }
@@ -29,6 +33,9 @@ func (s *String) Add(right Object) Object {
 		return CANT_OPERATE
 	}
 }
+func (s *String) Dot(right Object) Object {
+	return s.Add(right)
+}
 func (s *String) Multiply(right Object) Object {
 	switch right := right.(type) {
 	case *Int:
@@ -1,33 +1,59 @@
 package parser
 
 import (
-	"fmt"
+	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/token"
 )
 
-func (p *Parser) syntaxError(message string) {
-	p.Errors.AddParserError(message, p.curToken)
+func (p *Parser) syntaxError(code errorhandler.ErrorCode, message errorhandler.Message) {
+	// an error at the same place as the previous one follows from it
+	if n := len(p.Errors.ParserErrors); n > 0 {
+		last := p.Errors.ParserErrors[n-1].Token
+		if last.Line == p.curToken.Line && last.Column == p.curToken.Column {
+			return
+		}
+	}
+	p.Errors.AddParserError(code, message, p.curToken)
 }
 
-// func (p *Parser) syntaxErrorImportant(message string) {
-// 	p.Errors.AddParserErrorImportant(message, p.curToken)
+// func (p *Parser) syntaxErrorImportant(code errorhandler.ErrorCode, message errorhandler.Message) {
+// 	p.Errors.AddParserErrorImportant(code, message, p.curToken)
 // }
 
 func (p *Parser) invalidSyntax() {
-	p.Errors.AddParserError("Cú pháp không hợp lệ", p.curToken)
+	p.syntaxError(errorhandler.INVALID_SYNTAX, errorhandler.InvalidSyntax)
 }
 
 func (p *Parser) invalidIndent() {
-	p.syntaxError("Thụt dòng không hợp lệ")
+	p.syntaxError(errorhandler.INVALID_INDENT, errorhandler.InvalidIndent)
 }
 
 func (p *Parser) expectError(tokType token.TokenType) {
-	var msg string
+	var msg errorhandler.Message
 
 	if p.curIsStatementSeperator() {
-		msg = fmt.Sprintf("Thiếu '%s'", string(tokType))
+		msg = errorhandler.MissingToken.With(string(tokType))
 	} else {
-		msg = fmt.Sprintf("Cần '%s' thay vì '%s'", string(tokType), string(p.curToken.Literal))
+		msg = errorhandler.ExpectedToken.With(string(tokType), string(p.curToken.Literal))
+	}
+	p.syntaxError(errorhandler.UNEXPECTED_TOKEN, msg)
+}
+
+// synchronize recovers from the errors of a statement that started with
+// count errors: only its first error is kept since the others usually follow
+// from it, and the rest of the statement is skipped so the next one is parsed
+// on its own. Errors kept by statements nested inside it stay as they are
+func (p *Parser) synchronize(count int) {
+	start := count
+	if p.settledErrors > start {
+		start = p.settledErrors
+	}
+	if len(p.Errors.ParserErrors) > start {
+		p.Errors.ParserErrors = p.Errors.ParserErrors[:start+1]
+
+		for !p.curIsStatementSeperator() {
+			p.advanceToken()
+		}
 	}
-	p.syntaxError(msg)
+	p.settledErrors = len(p.Errors.ParserErrors)
 }
@@ -3,7 +3,10 @@ package parser
 import (
 	"math"
 	"math/big"
+	"strings"
+	"unicode"
 	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/token"
 )
 
@@ -15,10 +18,14 @@ const (
 	BELONG
 	EQUAL   // ==
 	COMPARE // > or <
+	BITOR   // | or xor
+	BITAND  // &
+	SHIFT   // << or >>
 	SUM     // +
 	PRODUCT // *
 	EXP     // ^
 	PREFIX
+	POSTFIX // 5!
 	CALL
 	Compose // .
 )
@@ -34,7 +41,7 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 	for !p.peekIsStatementSeperator() && precedence < p.peekPrecedence() {
 		infix := p.infixParseFns[p.peekToken.Type]
 		if infix == nil {
-			p.syntaxError("toán tử trung tố không tồn tại")
+			p.syntaxError(errorhandler.INVALID_SYNTAX, errorhandler.UnknownInfix)
 			return leftExp
 		}
 
@@ -51,10 +58,30 @@ func (p *Parser) parseIdentifier() ast.Expression {
 
 func (p *Parser) parseInt() ast.Expression {
 	i := &ast.Int{Token: p.curToken}
+	literal := p.curToken.Literal
 
-	value, check := new(big.Int).SetString(string(p.curToken.Literal), 10)
+	if len(literal) > 2 && literal[0] == '0' {
+		if base, ok := token.INT_BASES[literal[1]]; ok {
+			digits, ok := p.removeDigitSeparators(literal, base)
+			if !ok {
+				return nil
+			}
+			value, check := new(big.Int).SetString(digits[2:], base)
+			if !check {
+				p.syntaxError(errorhandler.INVALID_NUMBER, errorhandler.InvalidBasedInt.With(string(literal), base))
+			}
+			i.Value = value
+			return i
+		}
+	}
+
+	digits, ok := p.removeDigitSeparators(literal, 10)
+	if !ok {
+		return nil
+	}
+	value, check := new(big.Int).SetString(digits, 10)
 	if !check {
-		p.syntaxError("Không thể parse số nguyên này")
+		p.syntaxError(errorhandler.INVALID_NUMBER, errorhandler.InvalidInt)
 	}
 
 	i.Value = value
@@ -78,9 +105,18 @@ func (p *Parser) parseString() ast.Expression {
 func (p *Parser) parseReal() ast.Expression {
 	re := &ast.Real{Token: p.curToken}
 
-	value, check := new(big.Float).SetString(string(p.curToken.Literal))
+	if strings.Count(string(p.curToken.Literal), ".") > 1 {
+		errMsg := errorhandler.ManyDecimalPoints.With(string(p.curToken.Literal))
+		p.syntaxError(errorhandler.INVALID_NUMBER, errMsg)
+		return nil
+	}
+	digits, ok := p.removeDigitSeparators(p.curToken.Literal, 10)
+	if !ok {
+		return nil
+	}
+	value, check := new(big.Float).SetString(digits)
 	if !check {
-		p.syntaxError("Không thể parse số thực này")
+		p.syntaxError(errorhandler.INVALID_NUMBER, errorhandler.InvalidReal)
 	}
 
 	re.Value = value
@@ -97,6 +133,59 @@ func (p *Parser) parseReal() ast.Expression {
 	return re
 }
 
+// parseImaginary parses 3i or 2.5i, the coefficient is read like the number
+// without its 'i'
+func (p *Parser) parseImaginary() ast.Expression {
+	imaginary := &ast.Imaginary{Token: p.curToken}
+	literal := p.curToken.Literal
+
+	coefficient := p.curToken
+	coefficient.Type = token.Int
+	coefficient.Literal = literal[:len(literal)-1]
+	if strings.ContainsAny(string(coefficient.Literal), ".eE") {
+		coefficient.Type = token.Real
+	}
+
+	p.curToken = coefficient
+	if coefficient.Type == token.Real {
+		imaginary.Value = p.parseReal()
+	} else {
+		imaginary.Value = p.parseInt()
+	}
+	p.curToken = imaginary.Token
+
+	if imaginary.Value == nil {
+		return nil
+	}
+	return imaginary
+}
+
+// removeDigitSeparators strips the '_' of literals like 1_000_000, each of
+// them has to stand between two digits, or right after the prefix of 0x_FF
+func (p *Parser) removeDigitSeparators(literal []rune, base int) (string, bool) {
+	digits := make([]rune, 0, len(literal))
+
+	for ind, ch := range literal {
+		if ch != '_' {
+			digits = append(digits, ch)
+			continue
+		}
+		if ind == 0 || ind == len(literal)-1 ||
+			!(isDigitOf(literal[ind-1], base) || base != 10 && ind == 2) ||
+			!isDigitOf(literal[ind+1], base) {
+			msg := errorhandler.MisplacedSeparator.With(string(literal))
+			p.syntaxError(errorhandler.INVALID_NUMBER, msg)
+			return "", false
+		}
+	}
+	return string(digits), true
+}
+
+func isDigitOf(ch rune, base int) bool {
+	value := strings.IndexRune("0123456789abcdef", unicode.ToLower(ch))
+	return value >= 0 && value < base
+}
+
 func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: p.curToken, Value: p.curTokenIs(token.True)}
 }
@@ -105,7 +194,10 @@ func (p *Parser) parseList() ast.Expression {
 	leftBrace := p.curToken
 	p.advanceToken()
 
+	barEndsExpression := p.barEndsExpression
+	p.barEndsExpression = true
 	exp := p.parseExpression(LOWEST)
+	p.barEndsExpression = barEndsExpression
 
 	p.advanceToken()
 	if p.curTokenIs(token.Comma) || p.curTokenIs(token.RBrace) {
@@ -147,28 +239,132 @@ func (p *Parser) parseList() ast.Expression {
 		return list
 	}
 
+	// Set-builder notation
+	if p.curTokenIs(token.Colon) {
+		p.advanceToken()
+		first := p.parseExpression(LOWEST)
+
+		// { key: value với conditions } builds a map instead
+		if p.peekTokenIs(token.For) {
+			return p.parseMapComprehension(leftBrace, exp, first)
+		}
+
+		set := &ast.SetComprehension{LeftBrace: leftBrace}
+		set.Expression = exp
+		conditions := []ast.Expression{}
+		if first != nil {
+			conditions = append(conditions, first)
+		}
+
+		if p.peekTokenIs(token.Comma) {
+			p.advanceToken()
+			p.advanceToken()
+			conditions = append(conditions, p.parseExpressionList(token.RBrace)...)
+		}
+		for _, cond := range conditions {
+			set.Conditions = append(set.Conditions, splitConjunction(cond)...)
+		}
+
+		if !p.expectPeek(token.RBrace) {
+			return nil
+		}
+
+		set.RightBrace = p.curToken
+		return set
+	}
+
 	return nil
 }
 
+func (p *Parser) parseMapComprehension(leftBrace token.Token, key, value ast.Expression) ast.Expression {
+	mc := &ast.MapComprehension{LeftBrace: leftBrace, Key: key, Value: value}
+	p.advanceToken()
+	p.advanceToken()
+
+	for _, cond := range p.parseExpressionList(token.RBrace) {
+		mc.Conditions = append(mc.Conditions, splitConjunction(cond)...)
+	}
+
+	if !p.expectPeek(token.RBrace) {
+		return nil
+	}
+
+	mc.RightBrace = p.curToken
+	return mc
+}
+
+// splitConjunction turns 'x thuộc A và x > 2' into separate conditions, so
+// the 'thuộc' clause can be used as a generator
+func splitConjunction(exp ast.Expression) []ast.Expression {
+	infix, ok := exp.(*ast.InfixExpression)
+	if !ok || infix.Operator.Type != token.And {
+		return []ast.Expression{exp}
+	}
+	return append(splitConjunction(infix.Left), splitConjunction(infix.Right)...)
+}
+
+// parseInterval parses everything starting with '[': "[a, b]" is a real
+// interval, "[a..b]" an int interval, any other element count is an array.
 func (p *Parser) parseInterval() ast.Expression {
 	leftBracket := p.curToken
+
+	if p.peekTokenIs(token.RBracket) {
+		p.advanceToken()
+		return &ast.Array{LeftBracket: leftBracket, RightBracket: p.curToken}
+	}
 	p.advanceToken()
 
 	lower := p.parseExpression(LOWEST)
+	return p.parseIntervalRest(leftBracket, lower)
+}
+
+// parseIntervalRest continues an interval after its lower bound, a '(' or
+// ')' on either end makes that bound open: (a, b], [a, b), (a..b)
+func (p *Parser) parseIntervalRest(leftBracket token.Token, lower ast.Expression) ast.Expression {
+	lowerOpen := leftBracket.Type == token.LParen
 
 	p.advanceToken()
-	if p.curTokenIs(token.Comma) { // real interval
-		p.advanceToken()
+	if p.curTokenIs(token.Comma) {
+		exps := []ast.Expression{lower}
 
-		seg := &ast.RealInterval{
-			LeftBracket: leftBracket,
-			Lower:       lower,
-			Upper:       p.parseExpression(LOWEST),
+		if !p.peekTokenIs(token.RBracket) {
+			p.advanceToken()
+			exps = append(exps, p.parseExpressionList(token.RBracket)...)
 		}
-		if p.expectPeek(token.RBracket) {
-			seg.RightBracket = p.curToken
-			return seg
+		trailingComma := p.curTokenIs(token.Comma)
+
+		if len(exps) == 2 && !trailingComma && p.peekTokenIs(token.RParen) {
+			p.advanceToken()
+			return &ast.RealInterval{
+				LeftBracket:  leftBracket,
+				RightBracket: p.curToken,
+				Lower:        exps[0],
+				Upper:        exps[1],
+				LowerOpen:    lowerOpen,
+				UpperOpen:    true,
+			}
 		}
+		if lowerOpen && (len(exps) != 2 || trailingComma) {
+			p.syntaxError(errorhandler.INVALID_SYNTAX, errorhandler.OpenIntervalEndpoints)
+			return nil
+		}
+		if !p.expectPeek(token.RBracket) {
+			return nil
+		}
+
+		if len(exps) == 2 && !trailingComma { // real interval
+			return &ast.RealInterval{
+				LeftBracket:  leftBracket,
+				RightBracket: p.curToken,
+				Lower:        exps[0],
+				Upper:        exps[1],
+				LowerOpen:    lowerOpen,
+			}
+		}
+		return &ast.Array{LeftBracket: leftBracket, RightBracket: p.curToken, Data: exps}
+
+	} else if p.curTokenIs(token.RBracket) {
+		return &ast.Array{LeftBracket: leftBracket, RightBracket: p.curToken, Data: []ast.Expression{lower}}
 
 	} else if p.curTokenIs(token.DotDot) { // int interval
 		p.advanceToken()
@@ -176,12 +372,14 @@ func (p *Parser) parseInterval() ast.Expression {
 		seg := &ast.IntInterval{
 			LeftBracket: leftBracket,
 			Lower:       lower,
+			LowerOpen:   lowerOpen,
 		}
 
 		hasComma := false
 
-		if p.curTokenIs(token.RBracket) {
+		if p.curTokenIs(token.RBracket) || p.curTokenIs(token.RParen) {
 			seg.Upper = &ast.Real{Value: big.NewFloat(math.Inf(1))}
+			seg.RightBracket = p.curToken
 			return seg
 
 		} else if p.curTokenIs(token.Comma) {
@@ -200,6 +398,12 @@ func (p *Parser) parseInterval() ast.Expression {
 			seg.Step = p.parseExpression(LOWEST)
 		}
 
+		if p.peekTokenIs(token.RParen) {
+			p.advanceToken()
+			seg.RightBracket = p.curToken
+			seg.UpperOpen = true
+			return seg
+		}
 		if p.expectPeek(token.RBracket) {
 			seg.RightBracket = p.curToken
 			return seg
@@ -211,6 +415,35 @@ func (p *Parser) parseInterval() ast.Expression {
 	return nil
 }
 
+// parseQuantifier parses 'với mọi x thuộc A, x > 0' where the last expression
+// is the predicate, with a single condition the predicate may follow a colon
+// instead: 'tồn tại x thuộc A: x > 0'
+func (p *Parser) parseQuantifier() ast.Expression {
+	exp := &ast.QuantifierExpression{Token: p.curToken}
+	p.advanceToken()
+
+	exps := p.parseExpressionList()
+	if len(exps) == 0 {
+		return nil
+	}
+	if len(exps) == 1 {
+		if !p.expectPeek(token.Colon) {
+			return nil
+		}
+		p.advanceToken()
+
+		predicate := p.parseExpression(LOWEST)
+		if predicate == nil {
+			return nil
+		}
+		exps = append(exps, predicate)
+	}
+
+	exp.Conditions = exps[:len(exps)-1]
+	exp.Predicate = exps[len(exps)-1]
+	return exp
+}
+
 func (p *Parser) parseIfExpression(left ast.Expression) ast.Expression {
 	expression := &ast.IfExpression{Token: p.curToken, Consequence: left}
 
@@ -237,13 +470,35 @@ func (p *Parser) parseGroupExpression() ast.Expression {
 		p.indentLevel++
 	}
 
+	barEndsExpression := p.barEndsExpression
+	p.barEndsExpression = false
+
+	// the comma or '..' after the first expression tells (a, b) and (a..b]
+	// apart from a group
+	isAssign := p.curTokenIs(token.Ident) && p.peekTokenIs(token.Assign)
+	if _, isExpression := p.prefixParseFns[p.curToken.Type]; isExpression && !isAssign {
+		exp := p.parseExpression(LOWEST)
+
+		if p.peekTokenIs(token.Comma) || p.peekTokenIs(token.DotDot) {
+			p.barEndsExpression = barEndsExpression
+			return p.parseIntervalRest(block.LeftParen, exp)
+		}
+
+		block.Statements = append(block.Statements, &ast.ExpressionStatement{Expression: exp})
+		p.checkEndStatement()
+		p.updateIndentLevel()
+	}
+
 	for !p.curTokenIs(token.RParen) && !p.curTokenIs(token.EOF) {
 		stmt := p.parseStatement()
 		block.Statements = append(block.Statements, stmt)
 	}
+	p.barEndsExpression = barEndsExpression
 
+	// the rest of the file was read as the group, so the error points at the
+	// '(' that is never closed instead of the end of the file
 	if !p.curTokenIs(token.RParen) {
-		p.expectError(token.RParen)
+		p.Errors.AddParserError(errorhandler.UNEXPECTED_TOKEN, errorhandler.UnclosedParen, block.LeftParen)
 		return nil
 	}
 
@@ -272,8 +527,12 @@ func (p *Parser) parseCallArguments() []ast.Expression {
 		return args
 	}
 
+	barEndsExpression := p.barEndsExpression
+	p.barEndsExpression = false
+
 	p.advanceToken()
 	args = p.parseExpressionList(token.RParen)
+	p.barEndsExpression = barEndsExpression
 
 	if !p.expectPeek(token.RParen) {
 		return nil
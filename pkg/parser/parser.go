@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"sort"
 	"vanvo/pkg/ast"
 	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/lexer"
@@ -20,6 +21,7 @@ func New(l *lexer.Lexer, errors *errorhandler.ErrorList) *Parser {
 	p.registerPrefix(token.Ident, p.parseIdentifier)
 	p.registerPrefix(token.Int, p.parseInt)
 	p.registerPrefix(token.Real, p.parseReal)
+	p.registerPrefix(token.Imag, p.parseImaginary)
 	p.registerPrefix(token.String, p.parseString)
 	p.registerPrefix(token.True, p.parseBoolean)
 	p.registerPrefix(token.False, p.parseBoolean)
@@ -30,6 +32,9 @@ func New(l *lexer.Lexer, errors *errorhandler.ErrorList) *Parser {
 	p.registerPrefix(token.LParen, p.parseGroupExpression)
 	p.registerPrefix(token.LBracket, p.parseInterval)
 	p.registerPrefix(token.LBrace, p.parseList)
+	p.registerPrefix(token.Func, p.parseFunctionLiteral)
+	p.registerPrefix(token.ForAll, p.parseQuantifier)
+	p.registerPrefix(token.Exists, p.parseQuantifier)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.Plus, p.parseInfixExpression)
@@ -39,15 +44,25 @@ func New(l *lexer.Lexer, errors *errorhandler.ErrorList) *Parser {
 	p.registerInfix(token.Asterisk, p.parseInfixExpression)
 	p.registerInfix(token.Dot, p.parseInfixExpression)
 	p.registerInfix(token.Hat, p.parseInfixExpression)
+	p.registerInfix(token.Bar, p.parseInfixExpression)
+	p.registerInfix(token.Xor, p.parseInfixExpression)
+	p.registerInfix(token.Ampersand, p.parseInfixExpression)
+	p.registerInfix(token.LessLess, p.parseInfixExpression)
+	p.registerInfix(token.GreaterGreater, p.parseInfixExpression)
+	p.registerInfix(token.Union, p.parseInfixExpression)
+	p.registerInfix(token.Intersect, p.parseInfixExpression)
+	p.registerInfix(token.SetMinus, p.parseInfixExpression)
 	p.registerInfix(token.Equal, p.parseInfixExpression)
 	p.registerInfix(token.NotEqual, p.parseInfixExpression)
 	p.registerInfix(token.Less, p.parseInfixExpression)
 	p.registerInfix(token.Greater, p.parseInfixExpression)
 	p.registerInfix(token.LessEqual, p.parseInfixExpression)
 	p.registerInfix(token.GreaterEqual, p.parseInfixExpression)
+	p.registerInfix(token.Bang, p.parsePostfixExpression)
 	p.registerInfix(token.LParen, p.parseCallExpression)
 	p.registerInfix(token.If, p.parseIfExpression)
 	p.registerInfix(token.Belong, p.parseInfixExpression)
+	p.registerInfix(token.Is, p.parseInfixExpression)
 	p.registerInfix(token.And, p.parseInfixExpression)
 	p.registerInfix(token.Or, p.parseInfixExpression)
 	p.registerInfix(token.LBracket, p.parseIndexExpression)
@@ -63,6 +78,12 @@ type Parser struct {
 	Errors      *errorhandler.ErrorList
 	indentLevel int
 
+	// set while parsing the expression before '|' in a list comprehension
+	barEndsExpression bool
+
+	// number of errors kept by the recovery of the statements parsed so far
+	settledErrors int
+
 	curToken      token.Token
 	peekToken     token.Token
 	peekPeekToken token.Token
@@ -97,6 +118,15 @@ func (p *Parser) ParseProgram() *ast.Program {
 		program.Statements = append(program.Statements, stmt)
 	}
 
+	// a statement reports its error after the ones of the statements nested
+	// in it, they are put back in the order of the source
+	sort.SliceStable(p.Errors.ParserErrors, func(i, j int) bool {
+		a, b := p.Errors.ParserErrors[i].Token, p.Errors.ParserErrors[j].Token
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
 	return program
 }
 
@@ -138,6 +168,9 @@ func (p *Parser) expectCur(t token.TokenType) bool {
 	}
 }
 
+// An Endline token's literal is the leading whitespace of the line after it,
+// so only the last one of consecutive endlines tells the indentation: the
+// others come from blank lines, whatever whitespace they have.
 func (p *Parser) skipEndline() {
 	// skip semicolon
 	for p.curTokenIs(token.Semicolon) {
@@ -149,6 +182,7 @@ func (p *Parser) skipEndline() {
 	}
 }
 
+// updateIndentLevel reads the indentation of the next non blank line
 func (p *Parser) updateIndentLevel() {
 	p.skipEndline()
 
@@ -159,7 +193,7 @@ func (p *Parser) updateIndentLevel() {
 			return
 		}
 
-		level := length / 4
+		level := length / IDENT_SIZE
 		if level > p.indentLevel {
 			p.invalidIndent()
 			return
@@ -0,0 +1,538 @@
+package parser
+
+import (
+	"testing"
+	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
+	"vanvo/pkg/lexer"
+	"vanvo/pkg/token"
+)
+
+func testParse(t *testing.T, input string) *ast.Program {
+	errors := errorhandler.NewErrorList(input, "")
+	p := New(lexer.New(input, errors), errors)
+
+	program := p.ParseProgram()
+	if errors.NotEmpty() {
+		t.Fatalf("input %q has errors: \n%s", input, errors)
+	}
+	return program
+}
+
+func testParseError(t *testing.T, input string, expected string) {
+	errors := errorhandler.NewErrorList(input, "")
+	p := New(lexer.New(input, errors), errors)
+	p.ParseProgram()
+
+	if len(errors.ParserErrors) == 0 {
+		t.Fatalf("input %q expected parser error %q, got none", input, expected)
+	}
+	if errors.ParserErrors[0].Message != expected {
+		t.Errorf("input %q has wrong error. want=%q, got=%q", input, expected, errors.ParserErrors[0].Message)
+	}
+}
+
+func testIntValue(t *testing.T, input string, expected int64) {
+	program := testParse(t, input)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("input %q is not an expression statement. got=%T", input, program.Statements[0])
+	}
+	integer, ok := stmt.Expression.(*ast.Int)
+	if !ok {
+		t.Fatalf("input %q is not ast.Int. got=%T", input, stmt.Expression)
+	}
+	if integer.Value.Int64() != expected {
+		t.Errorf("input %q has wrong value. want=%d, got=%s", input, expected, integer.Value)
+	}
+}
+
+func TestBasedIntLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"0x1F", 31},
+		{"0XfF", 255},
+		{"0b1010", 10},
+		{"0o17", 15},
+		{"017", 17},
+		{"0", 0},
+	}
+
+	for _, test := range tests {
+		testIntValue(t, test.input, test.expected)
+	}
+}
+
+func TestBigIntLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775808", "9223372036854775808"},
+		{"123456789012345678901234567890", "123456789012345678901234567890"},
+		{"0xFFFFFFFFFFFFFFFFFFFF", "1208925819614629174706175"},
+		{"1_000_000_000_000_000_000_000", "1000000000000000000000"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		integer, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Int)
+		if !ok {
+			t.Fatalf("input %q is not ast.Int", test.input)
+		}
+		if integer.Value.String() != test.expected {
+			t.Errorf("input %q has wrong value. want=%s, got=%s", test.input, test.expected, integer.Value)
+		}
+	}
+}
+
+func TestInvalidBasedIntLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0xZZ", "'0xZZ' không phải số nguyên hệ 16 hợp lệ"},
+		{"0b102", "'0b102' không phải số nguyên hệ 2 hợp lệ"},
+		{"0o8", "'0o8' không phải số nguyên hệ 8 hợp lệ"},
+	}
+
+	for _, test := range tests {
+		testParseError(t, test.input, test.expected)
+	}
+}
+
+func TestInvalidBasedIntPosition(t *testing.T) {
+	input := "cho a = 1\ncho b = 0b12"
+	errors := errorhandler.NewErrorList(input, "")
+	New(lexer.New(input, errors), errors).ParseProgram()
+
+	if len(errors.ParserErrors) != 1 {
+		t.Fatalf("input %q expected 1 parser error, got %d", input, len(errors.ParserErrors))
+	}
+	tok := errors.ParserErrors[0].Token
+	if tok.Line != 2 || tok.Column != 9 || string(tok.Literal) != "0b12" {
+		t.Errorf("input %q has wrong error position. got line %d, column %d, literal %q",
+			input, tok.Line, tok.Column, string(tok.Literal))
+	}
+}
+
+func TestDigitSeparator(t *testing.T) {
+	testIntValue(t, "1_000_000", 1000000)
+	testIntValue(t, "0xFF_FF", 65535)
+	testIntValue(t, "0b1010_1010", 170)
+	testIntValue(t, "0x_FF", 255)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"3.141_592", "3.141592"},
+		{"1_000.5", "1000.5"},
+		{"1_0.0_1", "10.01"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		real, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Real)
+		if !ok {
+			t.Fatalf("input %q is not ast.Real", test.input)
+		}
+		if real.Value.Text('g', 10) != test.expected {
+			t.Errorf("input %q has wrong value. want=%s, got=%s", test.input, test.expected, real.Value.Text('g', 10))
+		}
+	}
+}
+
+func TestScientificNotationSeparator(t *testing.T) {
+	testParseError(t, "1_e5", "Dấu '_' trong '1_e5' phải nằm giữa hai chữ số")
+}
+
+func TestInvalidDigitSeparator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"5_", "Dấu '_' trong '5_' phải nằm giữa hai chữ số"},
+		{"5__0", "Dấu '_' trong '5__0' phải nằm giữa hai chữ số"},
+		{"3_.5", "Dấu '_' trong '3_.5' phải nằm giữa hai chữ số"},
+		{"3.5_", "Dấu '_' trong '3.5_' phải nằm giữa hai chữ số"},
+		{"0xFF_", "Dấu '_' trong '0xFF_' phải nằm giữa hai chữ số"},
+		{"1.2.3", "'1.2.3' có nhiều hơn một dấu chấm thập phân"},
+		{"1.2.3e5", "'1.2.3e5' có nhiều hơn một dấu chấm thập phân"},
+	}
+
+	for _, test := range tests {
+		testParseError(t, test.input, test.expected)
+	}
+}
+
+func testBlockSizes(t *testing.T, input string, expected []int) {
+	program := testParse(t, input)
+
+	if len(program.Statements) != len(expected) {
+		t.Fatalf("input %q has wrong number of statements. want=%d, got=%d",
+			input, len(expected), len(program.Statements))
+	}
+	for ind, stmt := range program.Statements {
+		size := 0
+		if stmt, ok := stmt.(*ast.IfStatement); ok {
+			size = len(stmt.Branches[0].Consequence.Statements)
+		}
+		if size != expected[ind] {
+			t.Errorf("input %q statement %d has wrong block size. want=%d, got=%d",
+				input, ind, expected[ind], size)
+		}
+	}
+}
+
+func TestBlankLinesInBlock(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []int
+	}{
+		{"nếu đúng:\n    1\n\n    2\n3", []int{2, 0}},
+		{"nếu đúng:\n\n    1\n    2", []int{2}},
+		{"nếu đúng:\n    1\n  \n    2", []int{2}},
+		{"nếu đúng:\n    1\n          \n    2\n3", []int{2, 0}},
+		{"nếu đúng:\n    1\n\t\n\n    2", []int{2}},
+		{"nếu đúng:\n    1\n    \n3", []int{1, 0}},
+		{"nếu đúng:\n    1\n\n\n", []int{1}},
+		{"nếu đúng:\r\n    1\r\n  \r\n    2\r\n3", []int{2, 0}},
+		{"nếu đúng:\n    nếu đúng:\n        1\n\n    2\n\n3", []int{2, 0}},
+		{"nếu đúng:\n    1\n// chú thích\n    2", []int{2}},
+	}
+
+	for _, test := range tests {
+		testBlockSizes(t, test.input, test.expected)
+	}
+}
+
+func TestInvalidIndent(t *testing.T) {
+	testParseError(t, "nếu đúng:\n    1\n      2", "Thụt dòng không hợp lệ")
+	testParseError(t, "1\n    2", "Thụt dòng không hợp lệ")
+	testParseError(t, "nếu đúng:\n\n1", "Thụt dòng không hợp lệ")
+}
+
+func TestOpenIntervalSyntax(t *testing.T) {
+	tests := []struct {
+		input     string
+		lowerOpen bool
+		upperOpen bool
+	}{
+		{"[a, b]", false, false},
+		{"(a, b)", true, true},
+		{"[a, b)", false, true},
+		{"(a, b]", true, false},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		interval, ok := stmt.Expression.(*ast.RealInterval)
+		if !ok {
+			t.Fatalf("input %q isn't a real interval. got=%T", test.input, stmt.Expression)
+		}
+		if interval.LowerOpen != test.lowerOpen || interval.UpperOpen != test.upperOpen {
+			t.Errorf("input %q has wrong bounds. want=(%v, %v), got=(%v, %v)", test.input,
+				test.lowerOpen, test.upperOpen, interval.LowerOpen, interval.UpperOpen)
+		}
+	}
+
+	testParseError(t, "(1, 2, 3)", "Khoảng mở chỉ có hai đầu mút")
+}
+
+func TestExponentAssociativity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a^b", "a^b"},
+		{"a^b^c", "a^(b^c)"},
+		{"a^b^c^d", "a^(b^(c^d))"},
+		{"a^b*c", "(a^b)*c"},
+		{"a*b^c^d", "a*(b^(c^d))"},
+		{"(a^b)^c", "(a^b)^c"},
+		{"a^b+c", "(a^b)+c"},
+		{"a+b^c^d", "a+(b^(c^d))"},
+		{"a^b^c*d", "(a^(b^c))*d"},
+		{"a^b^c == d", "(a^(b^c))==d"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		if got := exponentShape(stmt.Expression); got != test.expected {
+			t.Errorf("input %q has wrong shape. want=%s, got=%s", test.input, test.expected, got)
+		}
+	}
+}
+
+func TestLogicalPrecedence(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a == b và c < d", "(a==b)và(c<d)"},
+		{"a != b hoặc c >= d", "(a!=b)hoặc(c>=d)"},
+		{"a + 1 > b và c", "((a+1)>b)vàc"},
+		{"a và b hoặc c", "(avàb)hoặcc"},
+		{"a hoặc b và c", "(ahoặcb)vàc"},
+		{"a hoặc (b và c)", "ahoặc(bvàc)"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		if got := exponentShape(stmt.Expression); got != test.expected {
+			t.Errorf("input %q has wrong shape. want=%s, got=%s", test.input, test.expected, got)
+		}
+	}
+}
+
+func TestSignBeforeExponent(t *testing.T) {
+	program := testParse(t, "-2^2")
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+
+	prefix, ok := stmt.Expression.(*ast.PrefixExpression)
+	if !ok {
+		t.Fatalf("-2^2 should be a prefix expression, got %T", stmt.Expression)
+	}
+	if power, ok := prefix.Right.(*ast.InfixExpression); !ok || power.Operator.Type != token.Hat {
+		t.Errorf("the sign of -2^2 should apply to 2^2, got %q", prefix.Right.String())
+	}
+
+	// in a group the sign belongs to the base
+	program = testParse(t, "(-2)^2")
+	stmt = program.Statements[0].(*ast.ExpressionStatement)
+	if power, ok := stmt.Expression.(*ast.InfixExpression); !ok || power.Operator.Type != token.Hat {
+		t.Errorf("(-2)^2 should be a power, got %T", stmt.Expression)
+	}
+}
+
+// exponentShape writes an expression with parentheses around nested infix
+// expressions to show how it's grouped
+func exponentShape(exp ast.Expression) string {
+	if group, ok := exp.(*ast.ExpressionStatement); ok {
+		exp = group.Expression
+	}
+	infix, ok := exp.(*ast.InfixExpression)
+	if !ok {
+		return exp.String()
+	}
+	shape := func(side ast.Expression) string {
+		if group, ok := side.(*ast.ExpressionStatement); ok {
+			side = group.Expression
+		}
+		if _, nested := side.(*ast.InfixExpression); nested {
+			return "(" + exponentShape(side) + ")"
+		}
+		return exponentShape(side)
+	}
+	return shape(infix.Left) + string(infix.Operator.Literal) + shape(infix.Right)
+}
+
+func TestFunctionLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hàm(x) = x^2", "hàm(x) = x^2"},
+		{"hàm(a, b) = a + b", "hàm(a, b) = a+b"},
+		{"hàm() = 1", "hàm() = 1"},
+		{"f(hàm(x) = x, 2)", "f(hàm(x) = x, 2)"},
+	}
+
+	testParseError(t, "hàm(x) x", "Cần '=' thay vì 'x'")
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		if got := program.Statements[0].String(); got != test.expected {
+			t.Errorf("input %q parsed wrong. want=%q, got=%q", test.input, test.expected, got)
+		}
+	}
+}
+
+func TestMatchWithoutBranches(t *testing.T) {
+	tests := []string{
+		"khớp 1:",
+		"khớp 1:\n",
+		"cho x = 1\nkhớp x:\nxuất 2",
+		"cho x = 1\nkhớp x:\n\nxuất 2",
+		"nếu đúng:\n    khớp 1:\n    xuất 2",
+	}
+
+	for _, input := range tests {
+		testParseError(t, input, "Thiếu nhánh sau 'khớp'")
+	}
+}
+
+func TestDuplicateParams(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hàm f(x, x):\n    x", "Tham số 'x' bị lặp"},
+		{"cho f(a, b, a) = a + b", "Tham số 'a' bị lặp"},
+		{"cho g = hàm(y, y) = y", "Tham số 'y' bị lặp"},
+		{"(hàm(x, y, z, y) = 1)(1, 2, 3, 4)", "Tham số 'y' bị lặp"},
+	}
+
+	for _, test := range tests {
+		testParseError(t, test.input, test.expected)
+	}
+
+	testParse(t, "hàm f(x, y):\n    x\ncho g(x, x') = x\nhàm(a, b) = a")
+
+	// the parameters and the body after the repeated one are still parsed
+	for _, input := range []string{
+		"hàm f(x, x):\n    trả về x",
+		"hàm f(x, x, y):\n    trả về x + y\nxuất f(1, 2, 3)",
+		"cho g = hàm(y, y) = y\ng(1, 2)",
+	} {
+		errors := errorhandler.NewErrorList(input, "")
+		New(lexer.New(input, errors), errors).ParseProgram()
+		if len(errors.ParserErrors) != 1 {
+			t.Errorf("input %q should have exactly one error, got %d:\n%s",
+				input, len(errors.ParserErrors), errors)
+		}
+	}
+}
+
+func TestWhileStatement(t *testing.T) {
+	tests := []struct {
+		input     string
+		condition string
+	}{
+		{"khi n > 0:\n    n = n - 1", "n>0"},
+		{"trong khi i < 10: i = i + 1", "i<10"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		stmt, ok := program.Statements[0].(*ast.WhileStatement)
+		if !ok {
+			t.Fatalf("input %q should be a while loop, got %T", test.input, program.Statements[0])
+		}
+		if stmt.Condition.String() != test.condition || len(stmt.Body.Statements) != 1 {
+			t.Errorf("input %q has wrong parts. want %q and 1 statement, got %q and %d",
+				test.input, test.condition, stmt.Condition.String(), len(stmt.Body.Statements))
+		}
+	}
+
+	program := testParse(t, "ngoài: khi đúng:\n    dừng ngoài")
+	loop, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok || loop.Label == nil || loop.Label.Value != "ngoài" {
+		t.Errorf("expected a while loop labeled 'ngoài', got %#v", program.Statements[0])
+	}
+}
+
+func TestLoopLabel(t *testing.T) {
+	program := testParse(t, "vòng ngoài: với mỗi x thuộc A:\n    với y: thoát vòng ngoài")
+
+	loop, ok := program.Statements[0].(*ast.ForEachStatement)
+	if !ok || loop.Label == nil || loop.Label.Value != "vòng ngoài" {
+		t.Fatalf("expected a for each loop labeled 'vòng ngoài', got %#v", program.Statements[0])
+	}
+	inner := loop.Body.Statements[0].(*ast.ForStatement)
+	if inner.Label != nil {
+		t.Errorf("inner loop should have no label, got %q", inner.Label.Value)
+	}
+	stop := inner.Body.Statements[0].(*ast.BreakStatement)
+	if stop.Label == nil || stop.Label.Value != "vòng ngoài" {
+		t.Errorf("break should name 'vòng ngoài', got %q", stop.String())
+	}
+
+	testParseError(t, "ngoài: 1 + 2", "Nhãn 'ngoài' chỉ đặt được trước một vòng lặp")
+}
+
+func TestQuantifier(t *testing.T) {
+	tests := []struct {
+		input      string
+		conditions int
+		predicate  string
+	}{
+		{"với mọi x thuộc A, x > 0", 1, "x>0"},
+		{"tồn tại x thuộc A: x > 0", 1, "x>0"},
+		{"với mọi x thuộc A, y thuộc B, x < y", 2, "x<y"},
+		{"tồn tại x thuộc A, x > 1, x^2 == 4", 2, "x^2 == 4"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		exp, ok := stmt.Expression.(*ast.QuantifierExpression)
+		if !ok {
+			t.Fatalf("input %q should be a quantifier, got %T", test.input, stmt.Expression)
+		}
+		if len(exp.Conditions) != test.conditions || exp.Predicate.String() != test.predicate {
+			t.Errorf("input %q has wrong parts. want %d conditions and %q, got %d and %q",
+				test.input, test.conditions, test.predicate, len(exp.Conditions), exp.Predicate.String())
+		}
+	}
+
+	// without diacritics 'voi moi' is still 'với mỗi'
+	program := testParse(t, "voi moi x thuoc A: x")
+	if _, ok := program.Statements[0].(*ast.ForEachStatement); !ok {
+		t.Errorf("'voi moi' should be a for each loop, got %T", program.Statements[0])
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	input := "cho a = 1 )\nxuất a\ncho b = [1, 2\nxuất b\nhàm f(x, x): x\nxuất f(1)"
+	errors := errorhandler.NewErrorList(input, "")
+	program := New(lexer.New(input, errors), errors).ParseProgram()
+
+	expected := []struct {
+		line    int
+		message string
+	}{
+		{1, "Cú pháp không hợp lệ"},
+		{3, "Thiếu ']'"},
+		{5, "Tham số 'x' bị lặp"},
+	}
+	if len(errors.ParserErrors) != len(expected) {
+		t.Fatalf("input %q expected %d errors, got %d:\n%s",
+			input, len(expected), len(errors.ParserErrors), errors)
+	}
+	for ind, exp := range expected {
+		err := errors.ParserErrors[ind]
+		if err.Token.Line != exp.line || err.Message != exp.message {
+			t.Errorf("error %d is wrong. want line %d %q, got line %d %q",
+				ind, exp.line, exp.message, err.Token.Line, err.Message)
+		}
+	}
+	if len(program.Statements) != 6 {
+		t.Errorf("statements between the errors are lost, got %d statements", len(program.Statements))
+	}
+}
+
+func TestErrorOrder(t *testing.T) {
+	tests := []struct {
+		input string
+		first string
+	}{
+		{"xuất (1 +\nnếu đúng:\n    cho = 2\n", "'(' này chưa được đóng bằng ')'"},
+		{"với mỗi x thuộc (A:\n    cho = 1\n", "'(' này chưa được đóng bằng ')'"},
+		{"cho a = 1 )\nxuất (2", "Cú pháp không hợp lệ"},
+	}
+
+	for _, test := range tests {
+		errors := errorhandler.NewErrorList(test.input, "")
+		New(lexer.New(test.input, errors), errors).ParseProgram()
+
+		if len(errors.ParserErrors) == 0 || errors.ParserErrors[0].Message != test.first {
+			t.Errorf("input %q should report %q first, got:\n%s", test.input, test.first, errors)
+			continue
+		}
+		for ind := 1; ind < len(errors.ParserErrors); ind++ {
+			prev, cur := errors.ParserErrors[ind-1].Token, errors.ParserErrors[ind].Token
+			if prev.Line > cur.Line || prev.Line == cur.Line && prev.Column > cur.Column {
+				t.Errorf("input %q has errors out of order:\n%s", test.input, errors)
+				break
+			}
+		}
+	}
+}
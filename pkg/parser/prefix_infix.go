@@ -2,29 +2,40 @@ package parser
 
 import (
 	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/token"
 )
 
 var precedences = map[token.TokenType]int{
-	token.If:           IF,
-	token.And:          CONJUNC,
-	token.Or:           CONJUNC,
-	token.Belong:       BELONG,
-	token.Equal:        EQUAL,
-	token.NotEqual:     EQUAL,
-	token.Less:         COMPARE,
-	token.Greater:      COMPARE,
-	token.LessEqual:    COMPARE,
-	token.GreaterEqual: COMPARE,
-	token.Plus:         SUM,
-	token.Minus:        SUM,
-	token.Asterisk:     PRODUCT,
-	token.Slash:        PRODUCT,
-	token.Percent:      PRODUCT,
-	token.Hat:          EXP,
-	token.LParen:       CALL,
-	token.LBracket:     CALL,
-	token.Dot:          Compose,
+	token.If:             IF,
+	token.And:            CONJUNC,
+	token.Or:             CONJUNC,
+	token.Belong:         BELONG,
+	token.Is:             BELONG,
+	token.Equal:          EQUAL,
+	token.NotEqual:       EQUAL,
+	token.Less:           COMPARE,
+	token.Greater:        COMPARE,
+	token.LessEqual:      COMPARE,
+	token.GreaterEqual:   COMPARE,
+	token.Bar:            BITOR,
+	token.Xor:            BITOR,
+	token.Ampersand:      BITAND,
+	token.LessLess:       SHIFT,
+	token.GreaterGreater: SHIFT,
+	token.Union:          SUM,
+	token.SetMinus:       SUM,
+	token.Intersect:      PRODUCT,
+	token.Plus:           SUM,
+	token.Minus:          SUM,
+	token.Asterisk:       PRODUCT,
+	token.Slash:          PRODUCT,
+	token.Percent:        PRODUCT,
+	token.Hat:            EXP,
+	token.Bang:           POSTFIX,
+	token.LParen:         CALL,
+	token.LBracket:       CALL,
+	token.Dot:            Compose,
 }
 
 type (
@@ -41,6 +52,10 @@ func (p *Parser) registerInfix(tokenType token.TokenType, fn infixParseFn) {
 }
 
 func (p *Parser) peekPrecedence() int {
+	// '|' separates the expression and conditions of a list comprehension
+	if p.peekTokenIs(token.Bar) && p.barEndsExpression {
+		return LOWEST
+	}
 	if pre, ok := precedences[p.peekToken.Type]; ok {
 		return pre
 	}
@@ -61,16 +76,25 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 		Operator: p.curToken,
 	}
 
+	// a sign binds looser than '^' like in math: -2^2 is -(2^2)
+	precedence := PREFIX
+	if expr.Operator.Type == token.Minus || expr.Operator.Type == token.Plus {
+		precedence = PRODUCT
+	}
 	p.advanceToken()
-	expr.Right = p.parseExpression(PREFIX)
+	expr.Right = p.parseExpression(precedence)
 
 	if expr.Right == nil {
-		p.syntaxError("Tiền tố không tồn tại")
+		p.syntaxError(errorhandler.INVALID_SYNTAX, errorhandler.UnknownPrefix)
 	}
 
 	return expr
 }
 
+func (p *Parser) parsePostfixExpression(left ast.Expression) ast.Expression {
+	return &ast.PostfixExpression{Left: left, Operator: p.curToken}
+}
+
 func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	expr := &ast.InfixExpression{
 		Operator: p.curToken,
@@ -78,11 +102,15 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	}
 
 	precedence := p.curPrecedence()
+	// '^' is right-associative: 2^3^2 is 2^(3^2)
+	if expr.Operator.Type == token.Hat {
+		precedence--
+	}
 	p.advanceToken()
 	expr.Right = p.parseExpression(precedence)
 
 	if expr.Right == nil {
-		p.syntaxError("Thiếu vế phải của " + string(expr.Operator.Literal))
+		p.syntaxError(errorhandler.MISSING_OPERAND, errorhandler.MissingRightOperand.With(string(expr.Operator.Literal)))
 	}
 
 	// convert expression like a < b < c to (a < b) và (b < c)
@@ -2,15 +2,21 @@ package parser
 
 import (
 	"vanvo/pkg/ast"
+	"vanvo/pkg/errorhandler"
 	"vanvo/pkg/token"
 )
 
 func (p *Parser) parseStatement() ast.Statement {
 	defer p.updateIndentLevel()
+	defer p.synchronize(len(p.Errors.ParserErrors))
 	p.updateIndentLevel()
 
 	var stmt ast.Statement
 
+	if p.curTokenIs(token.Ident) && p.peekTokenIs(token.Colon) {
+		return p.parseLabeledLoop()
+	}
+
 	if p.curTokenIs(token.Ident) && p.peekTokenIs(token.Assign) {
 		stmt = p.parseAssignStatement()
 		p.checkEndStatement()
@@ -27,6 +33,10 @@ func (p *Parser) parseStatement() ast.Statement {
 		stmt = p.parseIfStatement()
 		return stmt
 
+	case token.Match:
+		stmt = p.parseMatchStatement()
+		return stmt
+
 	case token.For:
 		stmt = p.parseForStatement()
 		return stmt
@@ -35,9 +45,33 @@ func (p *Parser) parseStatement() ast.Statement {
 		stmt = p.parseForEachStatement()
 		return stmt
 
+	case token.While:
+		stmt = p.parseWhileStatement()
+		return stmt
+
+	case token.Repeat:
+		stmt = p.parseRepeatStatement()
+		return stmt
+
+	case token.Func:
+		if p.peekTokenIs(token.LParen) {
+			stmt = p.parseExpressionStatement()
+			break
+		}
+		stmt = p.parseFunctionStatement()
+		return stmt
+
 	case token.Imply:
 		stmt = p.parseImplyStatement()
 
+	case token.Break:
+		breakToken := p.curToken
+		stmt = &ast.BreakStatement{Token: breakToken, Label: p.parseLoopLabel()}
+
+	case token.Continue:
+		continueToken := p.curToken
+		stmt = &ast.ContinueStatement{Token: continueToken, Label: p.parseLoopLabel()}
+
 	case token.Output:
 		stmt = p.parseOutputStatement()
 
@@ -52,7 +86,10 @@ func (p *Parser) parseStatement() ast.Statement {
 
 func (p *Parser) checkEndStatement() {
 	p.advanceToken()
-	if !p.curIsStatementSeperator() && !p.curTokenIs(token.RParen) {
+	// an inline body can be followed by the next branch: nếu a: 1 ngược lại: 2
+	isNextBranch := p.curTokenIs(token.Else) || p.curTokenIs(token.ElseIf)
+
+	if !p.curIsStatementSeperator() && !p.curTokenIs(token.RParen) && !isNextBranch {
 		p.invalidSyntax()
 	}
 }
@@ -61,6 +98,12 @@ func (p *Parser) parseExpressionStatement() ast.Statement {
 	stmt := &ast.ExpressionStatement{}
 	stmt.Expression = p.parseExpression(LOWEST)
 
+	if target, ok := stmt.Expression.(*ast.IndexExpression); ok && p.peekTokenIs(token.Assign) {
+		p.advanceToken()
+		p.advanceToken()
+		return &ast.IndexAssignStatement{Target: target, Value: p.parseExpression(LOWEST)}
+	}
+
 	return stmt
 }
 
@@ -69,7 +112,7 @@ func (p *Parser) parseLetStatement() ast.Statement {
 
 	// Identifier
 	if !p.expectPeek(token.Ident) {
-		p.Errors.AddParserError("Sau 'cho' phải là một tên định danh", p.curToken)
+		p.syntaxError(errorhandler.UNEXPECTED_TOKEN, errorhandler.LetWithoutIdent)
 	}
 	ident := &ast.Identifier{Token: p.curToken, Value: string(p.curToken.Literal)}
 
@@ -95,35 +138,124 @@ func (p *Parser) parseLetStatement() ast.Statement {
 func (p *Parser) parseFunction(letToken token.Token, ident *ast.Identifier) *ast.FunctionDeclareStatement {
 	fn := &ast.FunctionDeclareStatement{Token: letToken, Ident: ident}
 
+	params, ok := p.parseFunctionParams()
+	if !ok {
+		return nil
+	}
+	fn.Params = params
+	p.advanceToken()
+
+	if !p.expectCur(token.Assign) {
+		return nil
+	}
+
+	fn.Body = p.parseExpression(LOWEST)
+
+	return fn
+}
+
+// parseFunctionStatement parses a function with a block body:
+//
+//	hàm f(x, y):
+//	    ...
+func (p *Parser) parseFunctionStatement() ast.Statement {
+	fnToken := p.curToken
+
+	if !p.expectPeek(token.Ident) {
+		return nil
+	}
+	fn := &ast.FunctionDeclareStatement{Token: fnToken}
+	fn.Ident = &ast.Identifier{Token: p.curToken, Value: string(p.curToken.Literal)}
+
+	if !p.expectPeek(token.LParen) {
+		return nil
+	}
+	params, ok := p.parseFunctionParams()
+	if !ok {
+		return nil
+	}
+	fn.Params = params
+
+	body := p.parseBlockStatement()
+	if body == nil {
+		return nil
+	}
+	fn.Body = body
+
+	return fn
+}
+
+// parseFunctionLiteral parses a function without a name:
+//
+//	hàm(x, y) = x + y
+func (p *Parser) parseFunctionLiteral() ast.Expression {
+	fn := &ast.FunctionLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.LParen) {
+		return nil
+	}
+	params, ok := p.parseFunctionParams()
+	if !ok {
+		return nil
+	}
+	fn.Params = params
+	p.advanceToken()
+
+	if !p.expectCur(token.Assign) {
+		return nil
+	}
+	fn.Body = p.parseExpression(LOWEST)
+	if fn.Body == nil {
+		return nil
+	}
+
+	return fn
+}
+
+// parseFunctionParams parses "(a, b, c)" and leaves curToken at ')'
+func (p *Parser) parseFunctionParams() ([]*ast.Identifier, bool) {
+	params := []*ast.Identifier{}
+
 	p.advanceToken()
 	for p.curTokenIs(token.Ident) {
 		param := p.parseIdentifier().(*ast.Identifier)
-		fn.Params = append(fn.Params, param)
+		// the error is kept but the rest of the function is still parsed, so
+		// its body doesn't give errors of its own
+		duplicate := false
+		for _, prev := range params {
+			if prev.Value == param.Value {
+				p.syntaxError(errorhandler.DUPLICATE_PARAM, errorhandler.DuplicateParam.With(param.Value))
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			params = append(params, param)
+		}
 
 		if p.peekTokenIs(token.RParen) {
 			p.advanceToken()
 			break
 		}
 		if !p.expectPeek(token.Comma) {
-			return nil
+			return nil, false
 		}
 		p.advanceToken()
 	}
-	if !p.expectCur(token.RParen) {
-		return nil
-	}
-
-	if !p.expectCur(token.Assign) {
-		return nil
+	if !p.curTokenIs(token.RParen) {
+		p.expectError(token.RParen)
+		return nil, false
 	}
 
-	fn.Body = p.parseExpression(LOWEST)
-
-	return fn
+	return params, true
 }
 
 func (p *Parser) parseImplyStatement() *ast.ImplyStatement {
 	stmt := &ast.ImplyStatement{Token: p.curToken}
+
+	// bare 'trả về' gives nothing
+	if p.peekIsStatementSeperator() || p.peekTokenIs(token.RParen) {
+		return stmt
+	}
 	p.advanceToken()
 
 	stmt.Value = p.parseExpression(LOWEST)
@@ -165,6 +297,98 @@ func (p *Parser) parseIfStatement() *ast.IfStatement {
 	return stmt
 }
 
+func (p *Parser) parseMatchStatement() *ast.MatchStatement {
+	stmt := &ast.MatchStatement{Token: p.curToken}
+	p.advanceToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.Colon) {
+		return nil
+	}
+	p.advanceToken()
+
+	// inline branches on the same line: khớp x: 0: "a"; _: "b"
+	if !p.curTokenIs(token.Endline) && !p.curTokenIs(token.EOF) {
+		for p.curToken.Line == stmt.Token.Line && !p.curTokenIs(token.EOF) {
+			stmt.Branches = append(stmt.Branches, p.parseMatchBranch())
+		}
+		return stmt
+	}
+
+	p.indentLevel++
+	curLevel := p.indentLevel
+
+	p.updateIndentLevel()
+	for p.indentLevel == curLevel && !p.curTokenIs(token.EOF) {
+		stmt.Branches = append(stmt.Branches, p.parseMatchBranch())
+		p.updateIndentLevel()
+	}
+
+	// the next line isn't indented or the file ends right after 'khớp x:'
+	if len(stmt.Branches) == 0 {
+		p.Errors.AddParserError(errorhandler.MISSING_OPERAND, errorhandler.MissingMatchBranch, stmt.Token)
+	}
+	return stmt
+}
+
+func (p *Parser) parseMatchBranch() *ast.MatchBranch {
+	branch := &ast.MatchBranch{Token: p.curToken}
+
+	// '_' is the default branch
+	isDefault := p.curTokenIs(token.Ident) && string(p.curToken.Literal) == "_"
+	if !isDefault {
+		branch.Pattern = p.parseExpression(LOWEST)
+	}
+	branch.Body = p.parseBlockStatement()
+
+	return branch
+}
+
+// parseLabeledLoop parses a loop named by a label so an inner loop can stop
+// it: vòng ngoài: với mỗi x thuộc A: ...
+func (p *Parser) parseLabeledLoop() ast.Statement {
+	label := &ast.Identifier{Token: p.curToken, Value: string(p.curToken.Literal)}
+	p.advanceToken()
+	p.advanceToken()
+
+	switch p.curToken.Type {
+	case token.For:
+		stmt := p.parseForStatement()
+		stmt.Label = label
+		return stmt
+
+	case token.ForEach:
+		stmt := p.parseForEachStatement()
+		stmt.Label = label
+		return stmt
+
+	case token.While:
+		stmt := p.parseWhileStatement()
+		stmt.Label = label
+		return stmt
+
+	case token.Repeat:
+		if stmt := p.parseRepeatStatement(); stmt != nil {
+			stmt.Label = label
+			return stmt
+		}
+		return nil
+	}
+
+	errMsg := errorhandler.MisplacedLabel.With(label.Value)
+	p.syntaxError(errorhandler.INVALID_SYNTAX, errMsg)
+	return nil
+}
+
+// parseLoopLabel reads the optional label after 'dừng' or 'tiếp'
+func (p *Parser) parseLoopLabel() *ast.Identifier {
+	if !p.peekTokenIs(token.Ident) {
+		return nil
+	}
+	p.advanceToken()
+	return &ast.Identifier{Token: p.curToken, Value: string(p.curToken.Literal)}
+}
+
 func (p *Parser) parseForStatement() *ast.ForStatement {
 	stmt := &ast.ForStatement{Token: p.curToken}
 	p.advanceToken()
@@ -175,6 +399,51 @@ func (p *Parser) parseForStatement() *ast.ForStatement {
 	return stmt
 }
 
+func (p *Parser) parseWhileStatement() *ast.WhileStatement {
+	stmt := &ast.WhileStatement{Token: p.curToken}
+	p.advanceToken()
+
+	stmt.Condition = p.parseExpression(LOWEST)
+	stmt.Body = p.parseBlockStatement()
+
+	return stmt
+}
+
+func (p *Parser) parseRepeatStatement() *ast.RepeatStatement {
+	stmt := &ast.RepeatStatement{Token: p.curToken}
+	p.advanceToken()
+
+	if p.curTokenIs(token.Ident) && p.peekTokenIs(token.From) {
+		stmt.Counter = &ast.Identifier{Token: p.curToken, Value: string(p.curToken.Literal)}
+		p.advanceToken()
+		p.advanceToken()
+
+		stmt.From = p.parseExpression(LOWEST)
+		if !p.expectPeek(token.To) {
+			return nil
+		}
+		p.advanceToken()
+		stmt.To = p.parseExpression(LOWEST)
+
+		if p.peekTokenIs(token.Step) {
+			p.advanceToken()
+			p.advanceToken()
+			stmt.Step = p.parseExpression(LOWEST)
+		}
+		stmt.Body = p.parseBlockStatement()
+
+		return stmt
+	}
+
+	stmt.Count = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.Times) {
+		return nil
+	}
+	stmt.Body = p.parseBlockStatement()
+
+	return stmt
+}
+
 func (p *Parser) parseForEachStatement() *ast.ForEachStatement {
 	stmt := &ast.ForEachStatement{Token: p.curToken}
 	p.advanceToken()
@@ -194,15 +463,26 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block.Statements = []ast.Statement{}
 	p.advanceToken()
 
+	// inline body on the same line: nếu a: 1
+	if !p.curTokenIs(token.Endline) && !p.curTokenIs(token.EOF) {
+		stmt := p.parseStatement()
+		block.Statements = append(block.Statements, stmt)
+		return block
+	}
+
 	p.indentLevel++
 	curLevel := p.indentLevel
 
-	if p.curTokenIs(token.Endline) && len(p.curToken.Literal)/4 < curLevel {
+	// blank lines may come before the first statement
+	if p.curTokenIs(token.Endline) {
+		p.skipEndline()
+	}
+	if p.curTokenIs(token.Endline) && len(p.curToken.Literal)/IDENT_SIZE < curLevel {
 		p.skipEndline()
 		p.invalidIndent()
 	}
 	if p.curTokenIs(token.EOF) {
-		p.syntaxError("Thiếu mệnh đề sau điều kiện")
+		p.syntaxError(errorhandler.MISSING_OPERAND, errorhandler.MissingConsequence)
 	}
 
 	for p.indentLevel == curLevel && !p.curTokenIs(token.EOF) {
@@ -1,6 +1,8 @@
 package token
 
-import "regexp"
+import (
+	"regexp"
+)
 
 var vnDiacriticRegexs = []struct {
 	regStr     string
@@ -30,3 +32,10 @@ func keywordsWithoutDiacritic(keywords map[string]TokenType) map[string]TokenTyp
 	}
 	return keywords
 }
+
+func withKeywords(keywords, more map[string]TokenType) map[string]TokenType {
+	for key, tok := range more {
+		keywords[key] = tok
+	}
+	return keywords
+}
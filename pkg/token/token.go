@@ -14,6 +14,7 @@ const (
 	Ident  = "IDENT"
 	Int    = "Số nguyên"
 	Real   = "Số thực"
+	Imag   = "Số ảo"
 	String = "Chuỗi"
 	True   = "đúng"
 	False  = "sai"
@@ -29,6 +30,11 @@ const (
 	Dot      = "."
 	Hash     = "#"
 
+	Ampersand      = "&"
+	Xor            = "xor"
+	LessLess       = "<<"
+	GreaterGreater = ">>"
+
 	Equal        = "=="
 	NotEqual     = "!="
 	Less         = "<"
@@ -39,16 +45,33 @@ const (
 	And = "và"
 	Or  = "hay"
 
-	Let     = "cho"
-	If      = "nếu"
-	ElseIf  = "còn nếu"
-	Else    = "còn không"
-	For     = "với"
-	ForEach = "với mỗi"
-	Belong  = "thuộc"
-	Imply   = "=>"
-	Input   = "nhập"
-	Output  = "xuất"
+	Let      = "cho"
+	If       = "nếu"
+	ElseIf   = "còn nếu"
+	Else     = "còn không"
+	For      = "với"
+	ForEach  = "với mỗi"
+	While    = "khi"
+	Belong   = "thuộc"
+	Is       = "là"
+	Match    = "khớp"
+	Imply    = "=>"
+	Input    = "nhập"
+	Output   = "xuất"
+	Func     = "hàm"
+	Repeat   = "lặp"
+	Times    = "lần"
+	Break    = "dừng"
+	Continue = "tiếp"
+	From     = "từ"
+	To       = "đến"
+	Step     = "bước"
+	ForAll   = "với mọi"
+	Exists   = "tồn tại"
+
+	Union     = "hợp"
+	Intersect = "giao"
+	SetMinus  = "trừ"
 
 	LParen     = "("
 	RParen     = ")"
@@ -62,6 +85,11 @@ const (
 	DotDot     = ".."
 	SlashSlash = "//"
 	Bar        = "|"
+
+	LParenAsterisk = "(*"
+	AsteriskRParen = "*)"
+	SlashAsterisk  = "/*"
+	AsteriskSlash  = "*/"
 )
 
 type Token struct {
@@ -75,7 +103,10 @@ func (t Token) String() string {
 	return fmt.Sprintf("{ Literal: %s, Type: %v, Line: %d, Column: %d }", string(t.Literal), t.Type, t.Line, t.Column)
 }
 
-var keywords = keywordsWithoutDiacritic(map[string]TokenType{
+// keywords of the first version also have a form without diacritics like
+// 'neu' for 'nếu'. The ones added later are only written with diacritics, bare
+// forms like 'tu' or 'lap' are too common as names
+var keywords = withKeywords(keywordsWithoutDiacritic(map[string]TokenType{
 	"cho":       Let,
 	"nếu":       If,
 	"còn nếu":   ElseIf,
@@ -89,11 +120,73 @@ var keywords = keywordsWithoutDiacritic(map[string]TokenType{
 	"hay":       Or,
 	"nhập":      Input,
 	"xuất":      Output,
+}), map[string]TokenType{
+	"ngược lại": Else,
+	"true":      True,
+	"false":     False,
+	"khớp":      Match,
+	"hoặc":      Or,
+	"xor":       Xor,
+	"trả về":    Imply,
+	"lặp":       Repeat,
+	"với mọi":   ForAll,
+	"tồn tại":   Exists,
 })
 
+// Context is where a soft keyword is read as a keyword, anywhere else the
+// word is part of an identifier like 'số lần' or 'trường hợp'
+type Context int
+
+const (
+	// Infix is between two operands, like 'A hợp B'
+	Infix Context = iota
+	// RepeatHeader is between 'lặp' and its ':'
+	RepeatHeader
+	// StatementStart is the first word of a statement, like 'dừng'
+	StatementStart
+	// FunctionHead is before '(' like 'hàm(x) = x', or before a name and '('
+	// at the start of a statement like 'hàm f(x):'
+	FunctionHead
+)
+
+type SoftKeyword struct {
+	Type    TokenType
+	Context Context
+}
+
+var softKeywords = map[string]SoftKeyword{
+	"là":        {Is, Infix},
+	"hợp":       {Union, Infix},
+	"giao":      {Intersect, Infix},
+	"trừ":       {SetMinus, Infix},
+	"mod":       {Percent, Infix},
+	"lần":       {Times, RepeatHeader},
+	"từ":        {From, RepeatHeader},
+	"đến":       {To, RepeatHeader},
+	"bước":      {Step, RepeatHeader},
+	"dừng":      {Break, StatementStart},
+	"thoát":     {Break, StatementStart},
+	"tiếp":      {Continue, StatementStart},
+	"khi":       {While, StatementStart},
+	"trong khi": {While, StatementStart},
+	"hàm":       {Func, FunctionHead},
+}
+
+// INT_BASES maps the prefix letter of integer literals like 0x1F to their base
+var INT_BASES = map[rune]int{
+	'x': 16, 'X': 16,
+	'o': 8, 'O': 8,
+	'b': 2, 'B': 2,
+}
+
 func LookupKeyword(word []rune) TokenType {
 	if tok, ok := keywords[string(word)]; ok {
 		return tok
 	}
 	return Ident
 }
+
+func LookupSoftKeyword(word []rune) (SoftKeyword, bool) {
+	keyword, ok := softKeywords[string(word)]
+	return keyword, ok
+}